@@ -1,6 +1,7 @@
 package adapters
 
 import (
+	"iter"
 	"statements/pkg/config"
 	"statements/pkg/transactions"
 )
@@ -24,16 +25,77 @@ func AdaptTransactions[T TransactionAdapter](ts []T) []TransactionAdapter {
 func FilterTransactions[T TransactionAdapter](ts []T, fs []config.Filter) []TransactionAdapter {
 	var res []TransactionAdapter
 	for _, t := range ts {
-		valid := true
-		for _, f := range fs {
-			if !f.Match(t.FieldValue(f.FieldName())) {
-				valid = false
-				break
-			}
-		}
-		if valid {
+		if matchFilters(t, fs) {
 			res = append(res, t)
 		}
 	}
 	return res
 }
+
+// Filters a stream of transactions based on the configured filters.
+func Filter[T TransactionAdapter](seq iter.Seq2[T, error], fs []config.Filter) iter.Seq2[T, error] {
+	return filter(seq, func(t T) bool {
+		return matchFilters(t, fs)
+	})
+}
+
+// Filters a stream of transactions based on the configured filters, keeping statement balances
+// regardless of the filters.
+//
+// Removed transactions that move money are passed to `dropped`, as the kept balances no longer
+// match the kept transactions without them.
+func FilterKeepingBalances[T TransactionAdapter](seq iter.Seq2[T, error], fs []config.Filter, dropped func(T)) iter.Seq2[T, error] {
+	return filter(seq, func(t T) bool {
+		if matchFilters(t, fs) {
+			return true
+		}
+		nt := t.Normalize()
+		if nt.IsMovement() {
+			dropped(t)
+		}
+		return nt.IsBalance()
+	})
+}
+
+// Keeps the transactions of a stream for which `keep` returns true.
+func filter[T TransactionAdapter](seq iter.Seq2[T, error], keep func(T) bool) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for t, err := range seq {
+			if err != nil {
+				yield(t, err)
+				return
+			}
+			if !keep(t) {
+				continue
+			}
+			if !yield(t, nil) {
+				return
+			}
+		}
+	}
+}
+
+// Normalizes a stream of bank transactions into the general format.
+func Normalize[T TransactionAdapter](seq iter.Seq2[T, error]) transactions.Seq {
+	return func(yield func(transactions.Transaction, error) bool) {
+		for t, err := range seq {
+			if err != nil {
+				yield(transactions.Transaction{}, err)
+				return
+			}
+			if !yield(t.Normalize(), nil) {
+				return
+			}
+		}
+	}
+}
+
+// Checks if a transaction matches all filters.
+func matchFilters(t TransactionAdapter, fs []config.Filter) bool {
+	for _, f := range fs {
+		if !f.Match(t.FieldValue(f.FieldName())) {
+			return false
+		}
+	}
+	return true
+}
@@ -254,3 +254,36 @@ func TestFilterTransactions_WithSwedbankTransactions(t *testing.T) {
 		}
 	}
 }
+
+func TestFilterAndNormalize(t *testing.T) {
+	mockTxs := []mockTransaction{
+		{date: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), value: 100, desc: "Keep"},
+		{date: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC), value: 200, desc: "Drop"},
+		{date: time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC), value: 300, desc: "Keep too"},
+	}
+	seq := func(yield func(mockTransaction, error) bool) {
+		for _, m := range mockTxs {
+			if !yield(m, nil) {
+				return
+			}
+		}
+	}
+	filters := []config.Filter{
+		config.StringFilter{
+			Field:      "description",
+			Condition:  config.StringContain,
+			Comparison: "Keep",
+		},
+	}
+
+	got, err := transactions.Collect(adapters.Normalize(adapters.Filter(seq, filters)))
+	if err != nil {
+		t.Fatalf("Normalize(Filter()) unexpected error: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("Normalize(Filter()) returned %d transactions, want 2", len(got))
+	}
+	if got[0].Value != 100 || got[1].Value != 300 {
+		t.Errorf("Normalize(Filter()) values = %d, %d, want 100, 300", got[0].Value, got[1].Value)
+	}
+}
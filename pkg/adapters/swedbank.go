@@ -2,6 +2,8 @@ package adapters
 
 import (
 	"fmt"
+	"iter"
+	"regexp"
 	"statements/pkg/config"
 	"statements/pkg/ctime"
 	"statements/pkg/transactions"
@@ -74,12 +76,17 @@ var SwedbankFieldMap = config.FieldMap{
 func NewSwedbankTransactions(rows [][]string) ([]SwedbankTransaction, error) {
 	var bts []SwedbankTransaction
 
-	for i := 1; i < len(rows); i++ {
-		row := rows[i]
-		bt, err := NewSwedbankTransaction(row)
+	seq := func(yield func([]string, error) bool) {
+		for _, row := range rows {
+			if !yield(row, nil) {
+				return
+			}
+		}
+	}
+
+	for bt, err := range ParseSwedbankTransactions(seq) {
 		if err != nil {
-			// TODO: Allow ignoring/warning instead of failing
-			return bts, fmt.Errorf("parsing failed on row %d: %v", i, err)
+			return bts, err
 		}
 		bts = append(bts, bt)
 	}
@@ -87,6 +94,34 @@ func NewSwedbankTransactions(rows [][]string) ([]SwedbankTransaction, error) {
 	return bts, nil
 }
 
+// Parses a stream of CSV rows into Swedbank transactions, skipping the header row.
+//
+// The stream stops at the first row that cannot be read or parsed.
+func ParseSwedbankTransactions(rows iter.Seq2[[]string, error]) iter.Seq2[SwedbankTransaction, error] {
+	return func(yield func(SwedbankTransaction, error) bool) {
+		i := -1
+		for row, err := range rows {
+			i++
+			if err != nil {
+				yield(SwedbankTransaction{}, err)
+				return
+			}
+			if i == 0 {
+				continue
+			}
+			bt, err := NewSwedbankTransaction(row)
+			if err != nil {
+				// TODO: Allow ignoring/warning instead of failing
+				yield(bt, fmt.Errorf("parsing failed on row %d: %v", i, err))
+				return
+			}
+			if !yield(bt, nil) {
+				return
+			}
+		}
+	}
+}
+
 // Creates a Swedbank transaction from a CSV row.
 func NewSwedbankTransaction(row []string) (SwedbankTransaction, error) {
 	var t SwedbankTransaction
@@ -182,16 +217,60 @@ func (t SwedbankTransaction) Normalize() transactions.Transaction {
 	}
 
 	nt = transactions.Transaction{
-		Date:          t.Date,
-		AccountHolder: t.AccountHolder,
-		Description:   t.Description,
-		Value:         nv,
-		Currency:      t.Currency,
+		Kind:                t.EntryType.Kind(),
+		Account:             t.AccountNumber,
+		Date:                t.Date,
+		ValueDate:           t.Date,
+		AccountHolder:       t.AccountHolder,
+		CounterpartyAccount: findIban(t.AccountHolder + " " + t.Description),
+		Description:         t.Description,
+		Value:               nv,
+		Currency:            t.Currency,
+		Reference:           t.ReferenceNumber,
+		BankReference:       t.ArchiveCode,
+		DocumentNumber:      t.DocumentNumber,
+		BankCode:            string(t.TransactionType),
+		Metadata: map[string]string{
+			"bank":      string(transactions.BankSwedbank),
+			"entryType": string(t.EntryType),
+			"flow":      string(t.Flow),
+		},
 	}
+	nt.ID = nt.DeriveID()
 
 	return nt
 }
 
+// The normalized kind of statement entry represented by the entry type.
+func (e SwedbankEntryType) Kind() transactions.Kind {
+	switch e {
+	case SwedbankEntryStartBalance:
+		return transactions.KindOpeningBalance
+	case SwedbankEntryTurnover:
+		return transactions.KindTurnover
+	case SwedbankEntryEndBalance:
+		return transactions.KindClosingBalance
+	case SwedbankEntryCurrentInterest:
+		return transactions.KindInterest
+	default:
+		return transactions.KindTransaction
+	}
+}
+
+var ibanPattern = regexp.MustCompile(`\b[A-Z]{2}[0-9]{2}[A-Z0-9]{11,30}\b`)
+
+// Finds the first IBAN mentioned in free text, as Swedbank does not export the
+// counterparty account in a dedicated column.
+func findIban(s string) string {
+	for _, m := range ibanPattern.FindAllString(s, -1) {
+		// Creditor references share the IBAN layout but use the "RF" prefix.
+		if !strings.HasPrefix(m, "RF") {
+			return m
+		}
+	}
+	return ""
+}
+
 // Parses an entry type from a raw string into an enum.
 func parseEntryType(t string) (SwedbankEntryType, error) {
 	switch t {
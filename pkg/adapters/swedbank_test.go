@@ -2,6 +2,7 @@ package adapters_test
 
 import (
 	"statements/pkg/adapters"
+	"statements/pkg/transactions"
 	"testing"
 	"time"
 )
@@ -62,3 +63,74 @@ func TestNewSwedbankTransaction(t *testing.T) {
 		})
 	}
 }
+
+func TestSwedbankTransaction_Normalize(t *testing.T) {
+	st := adapters.SwedbankTransaction{
+		AccountNumber:   "LV02HABA0123456789012",
+		EntryType:       adapters.SwedbankEntryTransaction,
+		Date:            time.Date(2025, 10, 31, 0, 0, 0, 0, time.UTC),
+		AccountHolder:   "TEST SHOP",
+		Description:     "INVOICE 12 LV80BANK0000435195001",
+		Value:           1250,
+		Currency:        "EUR",
+		Flow:            adapters.SwedbankDebit,
+		ArchiveCode:     "2025103101234567",
+		TransactionType: adapters.SwedbankTransactionToBank,
+		ReferenceNumber: "RF18539007547034",
+		DocumentNumber:  "42",
+	}
+
+	got := st.Normalize()
+
+	if got.ID != "2025103101234567" {
+		t.Errorf("ID = %q, want 2025103101234567", got.ID)
+	}
+	if got.Kind != transactions.KindTransaction {
+		t.Errorf("Kind = %q, want %q", got.Kind, transactions.KindTransaction)
+	}
+	if got.Account != "LV02HABA0123456789012" {
+		t.Errorf("Account = %q, want LV02HABA0123456789012", got.Account)
+	}
+	if got.Value != -1250 {
+		t.Errorf("Value = %d, want -1250", got.Value)
+	}
+	if !got.ValueDate.Equal(st.Date) {
+		t.Errorf("ValueDate = %v, want %v", got.ValueDate, st.Date)
+	}
+	if got.CounterpartyAccount != "LV80BANK0000435195001" {
+		t.Errorf("CounterpartyAccount = %q, want LV80BANK0000435195001", got.CounterpartyAccount)
+	}
+	if got.Reference != "RF18539007547034" {
+		t.Errorf("Reference = %q, want RF18539007547034", got.Reference)
+	}
+	if got.BankReference != "2025103101234567" {
+		t.Errorf("BankReference = %q, want 2025103101234567", got.BankReference)
+	}
+	if got.DocumentNumber != "42" {
+		t.Errorf("DocumentNumber = %q, want 42", got.DocumentNumber)
+	}
+	if got.BankCode != "INB" {
+		t.Errorf("BankCode = %q, want INB", got.BankCode)
+	}
+	if got.Metadata["flow"] != "D" {
+		t.Errorf("Metadata[flow] = %q, want D", got.Metadata["flow"])
+	}
+}
+
+func TestSwedbankEntryType_Kind(t *testing.T) {
+	tests := []struct {
+		entry adapters.SwedbankEntryType
+		want  transactions.Kind
+	}{
+		{adapters.SwedbankEntryStartBalance, transactions.KindOpeningBalance},
+		{adapters.SwedbankEntryTransaction, transactions.KindTransaction},
+		{adapters.SwedbankEntryTurnover, transactions.KindTurnover},
+		{adapters.SwedbankEntryEndBalance, transactions.KindClosingBalance},
+		{adapters.SwedbankEntryCurrentInterest, transactions.KindInterest},
+	}
+	for _, tt := range tests {
+		if got := tt.entry.Kind(); got != tt.want {
+			t.Errorf("Kind(%q) = %q, want %q", tt.entry, got, tt.want)
+		}
+	}
+}
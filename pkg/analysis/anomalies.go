@@ -0,0 +1,166 @@
+package analysis
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"statements/pkg/transactions"
+	"strings"
+	"time"
+)
+
+// The reason a transaction is considered unusual.
+type AnomalyKind string
+
+const (
+	// A charge much larger than usual for its counterparty or category.
+	AnomalyOutlier AnomalyKind = "outlier"
+	// A charge of the same amount to the same counterparty shortly after another one.
+	AnomalyDuplicate AnomalyKind = "duplicate"
+	// A large charge to a counterparty never paid before.
+	AnomalyNewPayee AnomalyKind = "new_payee"
+)
+
+// An unusual transaction together with an explanation.
+type Anomaly struct {
+	Transaction transactions.Transaction
+	Kind        AnomalyKind
+	Reason      string
+}
+
+// Options of anomaly detection.
+type AnomalyOptions struct {
+	// How many robust standard deviations above the median a charge must be to be an outlier,
+	// defaulting to 3.5.
+	Threshold float64
+	// The number of earlier charges a baseline needs before outliers are reported, defaulting to 5.
+	MinHistory int
+	// The number of days within which equal charges are possible duplicates, defaulting to 3.
+	DuplicateDays int
+	// The amount in minor units from which charges to new counterparties are reported, defaulting
+	// to 10000.
+	NewPayeeAmount int
+	// The date from which anomalies are reported, with earlier transactions only building the
+	// baselines. Defaults to 30 days after the first transaction.
+	Since time.Time
+}
+
+// The minimum spread of a baseline relative to its median, so that a constant amount does not
+// turn every deviation into an outlier.
+const minSpread = 0.1
+
+// The earlier charges of a counterparty or category, in absolute minor units.
+type baseline []int
+
+// Scores how unusual a charge is, returning the median it is compared to.
+func (b baseline) score(v int) (float64, int) {
+	sorted := slices.Clone(b)
+	slices.Sort(sorted)
+	median := sorted[len(sorted)/2]
+
+	deviations := make([]int, len(sorted))
+	for i, s := range sorted {
+		deviations[i] = abs(s - median)
+	}
+	slices.Sort(deviations)
+	spread := math.Max(1.4826*float64(deviations[len(deviations)/2]), minSpread*float64(median))
+
+	return float64(v-median) / math.Max(spread, 1), median
+}
+
+// Finds unusual charges, ordered by date.
+//
+// Transactions are compared to the history preceding them, so that every charge is judged by
+// what was known when it happened. Only charges are checked, as unusual income rarely needs
+// attention.
+func FindAnomalies(ts []transactions.Transaction, opts AnomalyOptions) []Anomaly {
+	if opts.Threshold <= 0 {
+		opts.Threshold = 3.5
+	}
+	if opts.MinHistory <= 0 {
+		opts.MinHistory = 5
+	}
+	if opts.DuplicateDays <= 0 {
+		opts.DuplicateDays = 3
+	}
+	if opts.NewPayeeAmount <= 0 {
+		opts.NewPayeeAmount = 10000
+	}
+
+	charges := make([]transactions.Transaction, 0, len(ts))
+	for _, t := range ts {
+		if t.IsMovement() && t.Value < 0 {
+			charges = append(charges, t)
+		}
+	}
+	slices.SortStableFunc(charges, func(a, b transactions.Transaction) int { return a.Date.Compare(b.Date) })
+	if len(charges) == 0 {
+		return nil
+	}
+	if opts.Since.IsZero() {
+		opts.Since = charges[0].Date.AddDate(0, 0, 30)
+	}
+
+	payees := map[string]baseline{}
+	categories := map[string]baseline{}
+	latest := map[string][]transactions.Transaction{}
+
+	var as []Anomaly
+	for _, t := range charges {
+		v := -t.Value
+		name := Counterparty(t)
+		payee := strings.ToLower(strings.TrimSpace(name)) + "\x1f" + t.Currency
+		category := t.Category + "\x1f" + t.Currency
+
+		// Only the charges within the duplicate window are kept.
+		window := t.Date.AddDate(0, 0, -opts.DuplicateDays)
+		recent := slices.DeleteFunc(latest[payee], func(p transactions.Transaction) bool {
+			return p.Date.Before(window)
+		})
+
+		if !t.Date.Before(opts.Since) {
+			flag := func(kind AnomalyKind, format string, args ...any) {
+				as = append(as, Anomaly{Transaction: t, Kind: kind, Reason: fmt.Sprintf(format, args...)})
+			}
+
+			if history := payees[payee]; len(history) >= opts.MinHistory {
+				if score, median := history.score(v); score > opts.Threshold {
+					flag(AnomalyOutlier, "%.1f times the median of %s over %d earlier charges by %s",
+						float64(v)/float64(median), transactions.FormatValue(median, "."), len(history), name)
+				}
+			} else if history := categories[category]; t.Category != "" && len(history) >= opts.MinHistory {
+				if score, median := history.score(v); score > opts.Threshold {
+					flag(AnomalyOutlier, "%.1f times the median of %s over %d earlier charges in %s",
+						float64(v)/float64(median), transactions.FormatValue(median, "."), len(history), t.Category)
+				}
+			}
+
+			for _, p := range recent {
+				if p.Value == t.Value && p.ID != t.ID {
+					flag(AnomalyDuplicate, "same amount charged by %s on %s", name, p.Date.Format(time.DateOnly))
+					break
+				}
+			}
+
+			if _, seen := payees[payee]; !seen && v >= opts.NewPayeeAmount {
+				flag(AnomalyNewPayee, "first charge by %s, at least %s", name,
+					transactions.FormatValue(opts.NewPayeeAmount, "."))
+			}
+		}
+
+		payees[payee] = append(payees[payee], v)
+		if t.Category != "" {
+			categories[category] = append(categories[category], v)
+		}
+		latest[payee] = append(recent, t)
+	}
+
+	return as
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
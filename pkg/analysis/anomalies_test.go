@@ -0,0 +1,62 @@
+package analysis_test
+
+import (
+	"fmt"
+	"statements/pkg/analysis"
+	"statements/pkg/transactions"
+	"testing"
+	"time"
+)
+
+func charge(id string, d time.Time, holder string, v int) transactions.Transaction {
+	return transactions.Transaction{ID: id, Date: d, AccountHolder: holder, Value: v, Currency: "EUR"}
+}
+
+func TestFindAnomalies(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	var ts []transactions.Transaction
+	for i := range 8 {
+		ts = append(ts, charge(fmt.Sprint("u", i), start.AddDate(0, i, 0), "Utility", -5000-100*(i%3)))
+	}
+	ts = append(ts,
+		charge("u8", start.AddDate(0, 8, 0), "Utility", -10400),
+		charge("s1", start.AddDate(0, 8, 3), "Shop", -2000),
+		charge("s2", start.AddDate(0, 8, 5), "Shop", -2000),
+		charge("t1", start.AddDate(0, 8, 10), "Travel agency", -45000),
+		charge("c1", start.AddDate(0, 8, 11), "Cafe", -450),
+		transactions.Transaction{ID: "r1", Date: start.AddDate(0, 8, 12), AccountHolder: "Employer", Value: 300000, Currency: "EUR"},
+	)
+
+	as := analysis.FindAnomalies(ts, analysis.AnomalyOptions{})
+	want := []struct {
+		id   string
+		kind analysis.AnomalyKind
+	}{
+		{"u8", analysis.AnomalyOutlier},
+		{"s2", analysis.AnomalyDuplicate},
+		{"t1", analysis.AnomalyNewPayee},
+	}
+	if len(as) != len(want) {
+		t.Fatalf("FindAnomalies() found %d anomalies, want %d: %+v", len(as), len(want), as)
+	}
+	for i, w := range want {
+		if as[i].Transaction.ID != w.id || as[i].Kind != w.kind || as[i].Reason == "" {
+			t.Errorf("anomaly %d = %s %s %q, want %s %s with a reason", i, as[i].Transaction.ID, as[i].Kind, as[i].Reason, w.id, w.kind)
+		}
+	}
+}
+
+func TestFindAnomaliesSince(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	ts := []transactions.Transaction{
+		charge("1", start, "Landlord", -70000),
+		charge("2", start.AddDate(0, 0, 1), "Landlord", -70000),
+	}
+
+	if as := analysis.FindAnomalies(ts, analysis.AnomalyOptions{}); len(as) != 0 {
+		t.Errorf("FindAnomalies() = %+v, want none within the first 30 days", as)
+	}
+	if as := analysis.FindAnomalies(ts, analysis.AnomalyOptions{Since: start}); len(as) != 2 {
+		t.Errorf("FindAnomalies() found %d anomalies, want a new payee and a duplicate", len(as))
+	}
+}
@@ -0,0 +1,181 @@
+package analysis
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"statements/pkg/config"
+	"statements/pkg/transactions"
+	"strings"
+	"time"
+)
+
+// A budget from the configuration with its filters decoded.
+type Budget struct {
+	Name   string
+	Period config.BudgetPeriod
+	// The limit per period in minor units.
+	Limit     int
+	Currency  string
+	CarryOver bool
+	category  string
+	filters   []config.Filter
+}
+
+// Creates budgets from the configuration, defaulting to monthly periods.
+func NewBudgets(bs []config.Budget) ([]Budget, error) {
+	budgets := make([]Budget, len(bs))
+	for i, b := range bs {
+		fs, err := transactions.DecodeFilters(b.Filters)
+		if err != nil {
+			return nil, fmt.Errorf("budget %d: %v", i+1, err)
+		}
+
+		name := b.Name
+		if name == "" {
+			name = b.Category
+		}
+		if name == "" {
+			name = fmt.Sprintf("budget %d", i+1)
+		}
+
+		period := b.Period
+		switch period {
+		case "":
+			period = config.BudgetMonthly
+		case config.BudgetMonthly, config.BudgetYearly:
+		default:
+			return nil, fmt.Errorf("budget %d: unknown period %q", i+1, period)
+		}
+
+		budgets[i] = Budget{
+			Name:      name,
+			Period:    period,
+			Limit:     int(math.Round(b.Limit * 100)),
+			Currency:  b.Currency,
+			CarryOver: b.CarryOver,
+			category:  b.Category,
+			filters:   fs,
+		}
+	}
+	return budgets, nil
+}
+
+// Sets the currency of budgets without one to the currency of the transactions they count, as
+// a limit cannot count amounts of different currencies as one.
+//
+// Fails if a budget without a currency counts transactions of several currencies.
+func ResolveCurrencies(bs []Budget, ts []transactions.Transaction) error {
+	for i, b := range bs {
+		if b.Currency != "" {
+			continue
+		}
+		var currencies []string
+		for _, t := range ts {
+			if b.Matches(t) && !slices.Contains(currencies, t.Currency) {
+				currencies = append(currencies, t.Currency)
+			}
+		}
+		if len(currencies) > 1 {
+			slices.Sort(currencies)
+			return fmt.Errorf("budget %s has no currency but counts transactions in %s", b.Name, strings.Join(currencies, ", "))
+		}
+		if len(currencies) == 1 {
+			bs[i].Currency = currencies[0]
+		}
+	}
+	return nil
+}
+
+// Whether a transaction is counted against the budget.
+func (b Budget) Matches(t transactions.Transaction) bool {
+	if !t.IsMovement() {
+		return false
+	}
+	if b.Currency != "" && t.Currency != b.Currency {
+		return false
+	}
+	if b.category != "" && t.Category != b.category {
+		return false
+	}
+	return t.Matches(b.filters)
+}
+
+// The start and the exclusive end of the budget period containing a date.
+func (b Budget) PeriodOf(d time.Time) (time.Time, time.Time) {
+	if b.Period == config.BudgetYearly {
+		start := time.Date(d.Year(), 1, 1, 0, 0, 0, 0, d.Location())
+		return start, start.AddDate(1, 0, 0)
+	}
+	start := time.Date(d.Year(), d.Month(), 1, 0, 0, 0, 0, d.Location())
+	return start, start.AddDate(0, 1, 0)
+}
+
+// The state of a budget in the period containing a given date, with amounts in minor units.
+type BudgetStatus struct {
+	Budget   string
+	Currency string
+	// The period key, such as `2025-01` for months and `2025` for years.
+	Period string
+	Limit  int
+	// The amount left over from past periods, negative if they were overspent.
+	CarryOver int
+	// The net amount spent in the period so far, reduced by refunds.
+	Spent     int
+	Remaining int
+	// The spending at the end of the period if it continues at the current pace.
+	Projected int
+}
+
+// Computes the status of the budget on the date `asOf`, counting transactions up to that date.
+//
+// With carry-over, every period since the first transaction contributes its unspent or
+// overspent amount.
+func (b Budget) Status(ts []transactions.Transaction, asOf time.Time) BudgetStatus {
+	start, end := b.PeriodOf(asOf)
+
+	var first time.Time
+	spent := map[time.Time]int{}
+	for _, t := range ts {
+		if t.Date.After(asOf) {
+			continue
+		}
+		if first.IsZero() || t.Date.Before(first) {
+			first = t.Date
+		}
+		if b.Matches(t) {
+			ps, _ := b.PeriodOf(t.Date)
+			spent[ps] -= t.Value
+		}
+	}
+
+	s := BudgetStatus{
+		Budget:   b.Name,
+		Currency: b.Currency,
+		Period:   periodKey(b.Period, start),
+		Limit:    b.Limit,
+		Spent:    spent[start],
+	}
+
+	if b.CarryOver && !first.IsZero() {
+		for p, _ := b.PeriodOf(first); p.Before(start); _, p = b.PeriodOf(p) {
+			s.CarryOver += b.Limit - spent[p]
+		}
+	}
+
+	s.Remaining = s.Limit + s.CarryOver - s.Spent
+
+	elapsed := asOf.Sub(start).Hours()/24 + 1
+	total := end.Sub(start).Hours() / 24
+	s.Projected = int(math.Round(float64(s.Spent) * total / elapsed))
+
+	return s
+}
+
+// The key of a budget period, matching the keys of summaries.
+func periodKey(p config.BudgetPeriod, start time.Time) string {
+	if p == config.BudgetYearly {
+		return GroupYear.Key(transactions.Transaction{Date: start})
+	}
+	return GroupMonth.Key(transactions.Transaction{Date: start})
+}
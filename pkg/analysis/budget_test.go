@@ -0,0 +1,91 @@
+package analysis_test
+
+import (
+	"statements/pkg/analysis"
+	"statements/pkg/config"
+	"statements/pkg/transactions"
+	"testing"
+	"time"
+)
+
+func TestBudgetStatus(t *testing.T) {
+	day := func(m time.Month, d int) time.Time { return time.Date(2025, m, d, 0, 0, 0, 0, time.UTC) }
+	ts := []transactions.Transaction{
+		{Date: day(1, 5), Value: -30000, Currency: "EUR", Category: "Groceries"},
+		{Date: day(2, 5), Value: -45000, Currency: "EUR", Category: "Groceries"},
+		{Date: day(3, 2), Value: -10000, Currency: "EUR", Category: "Groceries"},
+		{Date: day(3, 4), Value: 2000, Currency: "EUR", Category: "Groceries"},
+		{Date: day(3, 5), Value: -5000, Currency: "EUR", Category: "Rent"},
+		{Date: day(3, 6), Value: -9000, Currency: "USD", Category: "Groceries"},
+		{Date: day(3, 20), Value: -9000, Currency: "EUR", Category: "Groceries"},
+	}
+
+	bs, err := analysis.NewBudgets([]config.Budget{
+		{Category: "Groceries", Limit: 400, Currency: "EUR", CarryOver: true},
+		{Name: "Everything", Filters: []config.RawFilter{}, Limit: 2000, Period: config.BudgetYearly, Currency: "EUR"},
+	})
+	if err != nil {
+		t.Fatalf("NewBudgets() unexpected error: %v", err)
+	}
+
+	s := bs[0].Status(ts, day(3, 10))
+	want := analysis.BudgetStatus{
+		Budget:    "Groceries",
+		Currency:  "EUR",
+		Period:    "2025-03",
+		Limit:     40000,
+		CarryOver: 5000,
+		Spent:     8000,
+		Remaining: 37000,
+		Projected: 24800,
+	}
+	if s != want {
+		t.Errorf("Status() = %+v, want %+v", s, want)
+	}
+
+	s = bs[1].Status(ts, day(3, 10))
+	if s.Budget != "Everything" || s.Period != "2025" || s.Spent != 88000 || s.CarryOver != 0 {
+		t.Errorf("yearly Status() = %+v, want 880.00 spent in 2025", s)
+	}
+}
+
+func TestNewBudgetsInvalidPeriod(t *testing.T) {
+	if _, err := analysis.NewBudgets([]config.Budget{{Category: "Groceries", Limit: 1, Period: "weekly"}}); err == nil {
+		t.Error("NewBudgets() expected an error for an unknown period")
+	}
+}
+
+func TestResolveCurrencies(t *testing.T) {
+	date := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	ts := []transactions.Transaction{
+		{Date: date, Value: -3000, Currency: "EUR", Category: "Groceries"},
+		{Date: date, Value: -9000, Currency: "USD", Category: "Travel"},
+		{Date: date, Value: -5000, Currency: "EUR", Category: "Travel"},
+	}
+
+	bs, err := analysis.NewBudgets([]config.Budget{
+		{Category: "Groceries", Limit: 400},
+		{Category: "Rent", Limit: 700},
+		{Category: "Travel", Limit: 100, Currency: "USD"},
+	})
+	if err != nil {
+		t.Fatalf("NewBudgets() unexpected error: %v", err)
+	}
+	if err := analysis.ResolveCurrencies(bs, ts); err != nil {
+		t.Fatalf("ResolveCurrencies() unexpected error: %v", err)
+	}
+	if bs[0].Currency != "EUR" || bs[1].Currency != "" || bs[2].Currency != "USD" {
+		t.Errorf("ResolveCurrencies() currencies = %q, %q, %q, want EUR, none and the configured USD", bs[0].Currency, bs[1].Currency, bs[2].Currency)
+	}
+	if s := bs[0].Status(ts, date); s.Currency != "EUR" || s.Spent != 3000 {
+		t.Errorf("Status() = %+v, want 30.00 EUR spent", s)
+	}
+
+	bs, err = analysis.NewBudgets([]config.Budget{{Category: "Travel", Limit: 100}})
+	if err != nil {
+		t.Fatalf("NewBudgets() unexpected error: %v", err)
+	}
+	if err := analysis.ResolveCurrencies(bs, ts); err == nil {
+		t.Error("ResolveCurrencies() expected an error for a budget counting several currencies")
+	}
+}
@@ -0,0 +1,149 @@
+package analysis
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"slices"
+	"statements/pkg/ctime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// A range of days, from the start up to but excluding the end.
+type Period struct {
+	Start time.Time
+	End   time.Time
+}
+
+var (
+	quarterPattern = regexp.MustCompile(`^(\d{4})-Q([1-4])$`)
+	weekPattern    = regexp.MustCompile(`^(\d{4})-W(\d{2})$`)
+)
+
+// Parses a period in the format of a summary key, or an inclusive range of dates.
+//
+// Supported are years (`2025`), quarters (`2025-Q3`), months (`2025-09`), ISO weeks (`2025-W36`),
+// days (`2025-09-05` or `05.09.2025`) and ranges of days (`01.09.2025..15.09.2025`).
+func ParsePeriod(v string) (Period, error) {
+	if from, to, ok := strings.Cut(v, ".."); ok {
+		start, err := parseDay(from)
+		if err != nil {
+			return Period{}, fmt.Errorf("invalid period %q: %v", v, err)
+		}
+		end, err := parseDay(to)
+		if err != nil {
+			return Period{}, fmt.Errorf("invalid period %q: %v", v, err)
+		}
+		if end.Before(start) {
+			return Period{}, fmt.Errorf("invalid period %q: ends before it starts", v)
+		}
+		return Period{start, end.AddDate(0, 0, 1)}, nil
+	}
+
+	if m := quarterPattern.FindStringSubmatch(v); m != nil {
+		year, _ := strconv.Atoi(m[1])
+		quarter, _ := strconv.Atoi(m[2])
+		start := time.Date(year, time.Month(3*quarter-2), 1, 0, 0, 0, 0, time.UTC)
+		return Period{start, start.AddDate(0, 3, 0)}, nil
+	}
+	if m := weekPattern.FindStringSubmatch(v); m != nil {
+		year, _ := strconv.Atoi(m[1])
+		week, _ := strconv.Atoi(m[2])
+		// January 4th is always in the first ISO week.
+		jan4 := time.Date(year, 1, 4, 0, 0, 0, 0, time.UTC)
+		start := jan4.AddDate(0, 0, -(int(jan4.Weekday())+6)%7+7*(week-1))
+		if y, w := start.ISOWeek(); y != year || w != week {
+			return Period{}, fmt.Errorf("invalid period %q: no such week", v)
+		}
+		return Period{start, start.AddDate(0, 0, 7)}, nil
+	}
+	if start, err := time.Parse("2006", v); err == nil {
+		return Period{start, start.AddDate(1, 0, 0)}, nil
+	}
+	if start, err := time.Parse("2006-01", v); err == nil {
+		return Period{start, start.AddDate(0, 1, 0)}, nil
+	}
+	if start, err := parseDay(v); err == nil {
+		return Period{start, start.AddDate(0, 0, 1)}, nil
+	}
+
+	return Period{}, fmt.Errorf("invalid period %q, must be a year, quarter, month, week, day or a range of days", v)
+}
+
+// Parses a day in ISO or little-endian format.
+func parseDay(v string) (time.Time, error) {
+	if d, err := time.Parse(time.DateOnly, v); err == nil {
+		return d, nil
+	}
+	return time.Parse(ctime.LittleEndianDateOnly, v)
+}
+
+// Whether a date falls into the period.
+func (p Period) Contains(d time.Time) bool {
+	return !d.Before(p.Start) && d.Before(p.End)
+}
+
+// The period of the same length directly before this one.
+//
+// Periods of whole months are shifted by months, so that the month before March is February.
+func (p Period) Previous() Period {
+	if p.Start.Day() == 1 && p.End.Day() == 1 {
+		months := 12*(p.End.Year()-p.Start.Year()) + int(p.End.Month()-p.Start.Month())
+		return Period{p.Start.AddDate(0, -months, 0), p.Start}
+	}
+	days := int(p.End.Sub(p.Start).Hours() / 24)
+	return Period{p.Start.AddDate(0, 0, -days), p.Start}
+}
+
+// Formats the period as an inclusive range of ISO dates.
+func (p Period) String() string {
+	return p.Start.Format(time.DateOnly) + ".." + p.End.AddDate(0, 0, -1).Format(time.DateOnly)
+}
+
+// The totals of a group in two periods.
+type Delta struct {
+	Key      string
+	Currency string
+	Current  Totals
+	Previous Totals
+}
+
+// The change of the net flow between the periods, negative if more money went out.
+func (d Delta) Change() int {
+	return d.Current.Net() - d.Previous.Net()
+}
+
+// Pairs the groups of two summaries, ordered by the size of their change.
+//
+// Groups only found in one of the summaries have empty totals in the other.
+func Compare(current, previous *Summary) []Delta {
+	idx := map[[2]string]int{}
+	var ds []Delta
+	add := func(g Group, current bool) {
+		key := [2]string{g.Key, g.Currency}
+		i, ok := idx[key]
+		if !ok {
+			i = len(ds)
+			idx[key] = i
+			ds = append(ds, Delta{Key: g.Key, Currency: g.Currency})
+		}
+		if current {
+			ds[i].Current = g.Totals
+		} else {
+			ds[i].Previous = g.Totals
+		}
+	}
+	for _, g := range current.Groups() {
+		add(g, true)
+	}
+	for _, g := range previous.Groups() {
+		add(g, false)
+	}
+
+	slices.SortStableFunc(ds, func(a, b Delta) int {
+		return cmp.Compare(abs(b.Change()), abs(a.Change()))
+	})
+	return ds
+}
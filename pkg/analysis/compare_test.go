@@ -0,0 +1,97 @@
+package analysis_test
+
+import (
+	"statements/pkg/analysis"
+	"statements/pkg/transactions"
+	"testing"
+	"time"
+)
+
+func TestParsePeriod(t *testing.T) {
+	tests := []struct {
+		period string
+		want   string
+	}{
+		{"2025", "2025-01-01..2025-12-31"},
+		{"2025-Q3", "2025-07-01..2025-09-30"},
+		{"2025-09", "2025-09-01..2025-09-30"},
+		{"2025-W01", "2024-12-30..2025-01-05"},
+		{"2025-09-05", "2025-09-05..2025-09-05"},
+		{"05.09.2025", "2025-09-05..2025-09-05"},
+		{"01.09.2025..15.09.2025", "2025-09-01..2025-09-15"},
+	}
+	for _, tt := range tests {
+		p, err := analysis.ParsePeriod(tt.period)
+		if err != nil {
+			t.Errorf("ParsePeriod(%q) unexpected error: %v", tt.period, err)
+			continue
+		}
+		if p.String() != tt.want {
+			t.Errorf("ParsePeriod(%q) = %s, want %s", tt.period, p, tt.want)
+		}
+	}
+
+	for _, invalid := range []string{"2025-13", "2025-W54", "15.09.2025..01.09.2025", "September"} {
+		if _, err := analysis.ParsePeriod(invalid); err == nil {
+			t.Errorf("ParsePeriod(%q) expected an error", invalid)
+		}
+	}
+}
+
+func TestPeriodPrevious(t *testing.T) {
+	tests := []struct {
+		period string
+		want   string
+	}{
+		{"2025-03", "2025-02-01..2025-02-28"},
+		{"2025-Q1", "2024-10-01..2024-12-31"},
+		{"2025-W02", "2024-12-30..2025-01-05"},
+		{"11.09.2025..20.09.2025", "2025-09-01..2025-09-10"},
+	}
+	for _, tt := range tests {
+		p, _ := analysis.ParsePeriod(tt.period)
+		if got := p.Previous().String(); got != tt.want {
+			t.Errorf("%s previous = %s, want %s", tt.period, got, tt.want)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	aug := time.Date(2025, 8, 10, 0, 0, 0, 0, time.UTC)
+	sep := time.Date(2025, 9, 10, 0, 0, 0, 0, time.UTC)
+	current := analysis.NewSummary(analysis.GroupCounterparty)
+	previous := analysis.NewSummary(analysis.GroupCounterparty)
+	for _, t := range []transactions.Transaction{
+		{Date: sep, AccountHolder: "Shop", Value: -3000, Currency: "EUR"},
+		{Date: sep, AccountHolder: "Airline", Value: -20000, Currency: "EUR"},
+	} {
+		current.Add(t)
+	}
+	for _, t := range []transactions.Transaction{
+		{Date: aug, AccountHolder: "Shop", Value: -2000, Currency: "EUR"},
+		{Date: aug, AccountHolder: "Gym", Value: -1500, Currency: "EUR"},
+	} {
+		previous.Add(t)
+	}
+
+	ds := analysis.Compare(current, previous)
+	want := []struct {
+		key    string
+		change int
+	}{
+		{"Airline", -20000},
+		{"Gym", 1500},
+		{"Shop", -1000},
+	}
+	if len(ds) != len(want) {
+		t.Fatalf("Compare() returned %d deltas, want %d", len(ds), len(want))
+	}
+	for i, w := range want {
+		if ds[i].Key != w.key || ds[i].Change() != w.change {
+			t.Errorf("delta %d = %s %d, want %s %d", i, ds[i].Key, ds[i].Change(), w.key, w.change)
+		}
+	}
+	if ds[0].Previous.Count != 0 || ds[1].Current.Count != 0 {
+		t.Error("new and disappeared counterparties have totals in both periods")
+	}
+}
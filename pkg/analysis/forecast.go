@@ -0,0 +1,205 @@
+package analysis
+
+import (
+	"fmt"
+	"math"
+	"statements/pkg/transactions"
+	"time"
+)
+
+// The average number of days in a month.
+const daysPerMonth = 30.44
+
+// Options of cash-flow forecasts.
+type ForecastOptions struct {
+	// The number of months to project, defaulting to 3.
+	Months int
+	// The currency to project, defaulting to the currency of the latest statement balance.
+	Currency string
+	// The balance to start from in minor units, used instead of the latest statement balance if
+	// `Start` is set.
+	Balance int
+	// The date of the starting balance.
+	Start time.Time
+}
+
+// The projected balance at the end of a day, in minor units.
+type ForecastPoint struct {
+	Date    time.Time
+	Balance int
+	// The balance one standard deviation of discretionary spending below the projection.
+	Low int
+	// The balance one standard deviation of discretionary spending above the projection.
+	High int
+}
+
+// Finds the latest balance of all accounts in a currency.
+//
+// Each account contributes its latest closing balance plus any transactions booked after it. The
+// returned date is the latest of these bookings. If `currency` is empty, the currency of the
+// latest closing balance is used.
+func LatestBalance(ts []transactions.Transaction, currency string) (int, string, time.Time, error) {
+	if currency == "" {
+		var latest time.Time
+		for _, t := range ts {
+			if t.Kind == transactions.KindClosingBalance && !t.Date.Before(latest) {
+				latest, currency = t.Date, t.Currency
+			}
+		}
+	}
+
+	closings := map[string]transactions.Transaction{}
+	for _, t := range ts {
+		if t.Kind != transactions.KindClosingBalance || t.Currency != currency {
+			continue
+		}
+		if c, ok := closings[t.Account]; !ok || !t.Date.Before(c.Date) {
+			closings[t.Account] = t
+		}
+	}
+	if len(closings) == 0 {
+		return 0, "", time.Time{}, fmt.Errorf("no closing balance found")
+	}
+
+	var latest time.Time
+	balance := 0
+	for account, c := range closings {
+		balance += c.Value
+		latest = maxDate(latest, c.Date)
+		for _, t := range ts {
+			if t.Account == account && t.Currency == currency && t.IsMovement() && t.Date.After(c.Date) {
+				balance += t.Value
+				latest = maxDate(latest, t.Date)
+			}
+		}
+	}
+	return balance, currency, latest, nil
+}
+
+// Projects the balance day by day from the starting balance.
+//
+// The projection combines the expected occurrences of active recurring transactions with the
+// average daily spending per category of all other charges. The band around the projection
+// grows with the monthly variation of that spending. Income that is not recurring is not
+// projected.
+func Forecast(ts []transactions.Transaction, opts ForecastOptions) ([]ForecastPoint, error) {
+	if opts.Months <= 0 {
+		opts.Months = 3
+	}
+	if opts.Start.IsZero() {
+		balance, currency, date, err := LatestBalance(ts, opts.Currency)
+		if err != nil {
+			return nil, err
+		}
+		opts.Balance, opts.Currency, opts.Start = balance, currency, date
+	}
+
+	// The history starts with the first transaction or balance before the forecast. Transfers
+	// between own accounts do not change the total balance, so they are left out.
+	var history []transactions.Transaction
+	first := opts.Start
+	for _, t := range ts {
+		if t.Currency != opts.Currency || t.Date.After(opts.Start) {
+			continue
+		}
+		first = minDate(first, t.Date)
+		if t.IsMovement() && !t.IsTransfer() {
+			history = append(history, t)
+		}
+	}
+
+	end := addMonths(opts.Start, opts.Months)
+	days := int(end.Sub(opts.Start).Hours() / 24)
+	deltas := make([]float64, days)
+
+	recurring := map[string]bool{}
+	for _, r := range FindRecurring(history, RecurringOptions{AsOf: opts.Start}) {
+		for _, t := range r.Transactions {
+			recurring[t.ID] = true
+		}
+		if !r.Active() {
+			continue
+		}
+		for n := 1; ; n++ {
+			d := r.Interval.After(r.Last, n)
+			if !d.Before(end) {
+				break
+			}
+			// Occurrences that are due but not booked yet are expected right away.
+			i := max(int(d.Sub(opts.Start).Hours()/24)-1, 0)
+			deltas[i] += float64(r.Average)
+		}
+	}
+
+	rate, variance := discretionary(history, recurring, opts.Start.Sub(first).Hours()/24+1)
+	points := make([]ForecastPoint, days)
+	balance := float64(opts.Balance)
+	for i := range days {
+		balance += deltas[i] + rate
+		spread := math.Sqrt(variance * float64(i+1) / daysPerMonth)
+		points[i] = ForecastPoint{
+			Date:    opts.Start.AddDate(0, 0, i+1),
+			Balance: int(math.Round(balance)),
+			Low:     int(math.Round(balance - spread)),
+			High:    int(math.Round(balance + spread)),
+		}
+	}
+	return points, nil
+}
+
+// Computes the average daily amount of charges that are not recurring over a history of the
+// provided number of days, together with the variance of their monthly totals summed over
+// categories.
+func discretionary(history []transactions.Transaction, recurring map[string]bool, days float64) (float64, float64) {
+	if len(history) == 0 {
+		return 0, 0
+	}
+
+	months := map[string]bool{}
+	totals := map[string]int{}
+	monthly := map[string]map[string]int{}
+	for _, t := range history {
+		month := GroupMonth.Key(t)
+		months[month] = true
+		if t.Value >= 0 || recurring[t.ID] {
+			continue
+		}
+		category := GroupCategory.Key(t)
+		totals[category] += t.Value
+		if monthly[category] == nil {
+			monthly[category] = map[string]int{}
+		}
+		monthly[category][month] += t.Value
+	}
+
+	rate, variance := 0.0, 0.0
+	for category, total := range totals {
+		rate += float64(total) / days
+
+		// Months without charges in the category count as zero.
+		mean := float64(total) / float64(len(months))
+		sum := 0.0
+		for month := range months {
+			diff := float64(monthly[category][month]) - mean
+			sum += diff * diff
+		}
+		if len(months) > 1 {
+			variance += sum / float64(len(months)-1)
+		}
+	}
+	return rate, variance
+}
+
+func minDate(a, b time.Time) time.Time {
+	if b.Before(a) {
+		return b
+	}
+	return a
+}
+
+func maxDate(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,98 @@
+package analysis_test
+
+import (
+	"fmt"
+	"statements/pkg/analysis"
+	"statements/pkg/transactions"
+	"testing"
+	"time"
+)
+
+func forecastHistory() []transactions.Transaction {
+	day := func(m time.Month, d int) time.Time { return time.Date(2025, m, d, 0, 0, 0, 0, time.UTC) }
+	var ts []transactions.Transaction
+	for i, m := range []time.Month{1, 2, 3} {
+		ts = append(ts, transactions.Transaction{ID: fmt.Sprint("r", i), Date: day(m, 1), Account: "A", AccountHolder: "Landlord", Value: -50000, Currency: "EUR"})
+	}
+	values := []int{-2000, -2000, -1000, -2000, -2000, -3000}
+	for i, d := range []time.Time{day(1, 3), day(1, 17), day(2, 9), day(2, 11), day(3, 2), day(3, 31)} {
+		ts = append(ts, transactions.Transaction{ID: fmt.Sprint("g", i), Date: d, Account: "A", AccountHolder: "Shop", Value: values[i], Currency: "EUR"})
+	}
+	return append(ts,
+		transactions.Transaction{Kind: transactions.KindClosingBalance, Date: day(3, 30), Account: "A", Value: 103000, Currency: "EUR"},
+		transactions.Transaction{Kind: transactions.KindClosingBalance, Date: day(3, 30), Account: "B", Value: 500, Currency: "USD"},
+	)
+}
+
+func TestLatestBalance(t *testing.T) {
+	balance, currency, date, err := analysis.LatestBalance(forecastHistory(), "EUR")
+	if err != nil {
+		t.Fatalf("LatestBalance() unexpected error: %v", err)
+	}
+	if balance != 100000 || currency != "EUR" || date.Day() != 31 {
+		t.Errorf("LatestBalance() = %d %s on %v, want 100000 EUR on March 31st", balance, currency, date)
+	}
+
+	if _, _, _, err := analysis.LatestBalance(forecastHistory()[:3], ""); err == nil {
+		t.Error("LatestBalance() expected an error without closing balances")
+	}
+}
+
+func TestForecast(t *testing.T) {
+	points, err := analysis.Forecast(forecastHistory(), analysis.ForecastOptions{Months: 1, Currency: "EUR"})
+	if err != nil {
+		t.Fatalf("Forecast() unexpected error: %v", err)
+	}
+	if len(points) != 30 {
+		t.Fatalf("Forecast() returned %d days, want 30", len(points))
+	}
+
+	// The rent is due on the first day, on top of 120.00 of shopping over 90 days.
+	first, last := points[0], points[len(points)-1]
+	if !first.Date.Equal(time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)) || first.Balance != 49867 {
+		t.Errorf("first day = %d on %v, want 49867 on 2025-04-01", first.Balance, first.Date)
+	}
+	if last.Balance != 46000 {
+		t.Errorf("last day = %d, want 46000", last.Balance)
+	}
+	if !(last.Low < last.Balance && last.Balance < last.High) || last.High-last.Low <= first.High-first.Low {
+		t.Errorf("band %d-%d does not widen around %d", last.Low, last.High, last.Balance)
+	}
+}
+
+func TestForecast_SparseHistory(t *testing.T) {
+	// A single charge in a 90 day history is spread over the whole history.
+	ts := []transactions.Transaction{
+		{Kind: transactions.KindOpeningBalance, Date: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), Account: "A", Value: 100000, Currency: "EUR"},
+		{ID: "c", Date: time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC), Account: "A", AccountHolder: "Cinema", Value: -1250, Currency: "EUR"},
+		{Kind: transactions.KindClosingBalance, Date: time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC), Account: "A", Value: 98750, Currency: "EUR"},
+	}
+	points, err := analysis.Forecast(ts, analysis.ForecastOptions{Months: 1, Currency: "EUR"})
+	if err != nil {
+		t.Fatalf("Forecast() unexpected error: %v", err)
+	}
+	if last := points[len(points)-1]; last.Balance != 98333 {
+		t.Errorf("last day = %d, want 98333", last.Balance)
+	}
+}
+
+func TestForecast_Transfers(t *testing.T) {
+	want, err := analysis.Forecast(forecastHistory(), analysis.ForecastOptions{Months: 1, Currency: "EUR"})
+	if err != nil {
+		t.Fatalf("Forecast() unexpected error: %v", err)
+	}
+
+	// A transfer to another own account leaves the projection unchanged.
+	day := time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC)
+	ts := append(forecastHistory(),
+		transactions.Transaction{ID: "t1", Date: day, Account: "A", CounterpartyAccount: "C", Value: -50000, Currency: "EUR", Transfer: "t2"},
+		transactions.Transaction{ID: "t2", Date: day, Account: "C", CounterpartyAccount: "A", Value: 50000, Currency: "EUR", Transfer: "t1"},
+	)
+	got, err := analysis.Forecast(ts, analysis.ForecastOptions{Months: 1, Currency: "EUR"})
+	if err != nil {
+		t.Fatalf("Forecast() unexpected error: %v", err)
+	}
+	if last := got[len(got)-1]; last != want[len(want)-1] {
+		t.Errorf("last day with a transfer = %+v, want %+v", last, want[len(want)-1])
+	}
+}
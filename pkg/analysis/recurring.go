@@ -0,0 +1,263 @@
+package analysis
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"statements/pkg/transactions"
+	"strings"
+	"time"
+)
+
+// The period between occurrences of a recurring transaction.
+type Interval string
+
+const (
+	IntervalWeekly  Interval = "weekly"
+	IntervalMonthly Interval = "monthly"
+	IntervalYearly  Interval = "yearly"
+)
+
+// The nominal length of each interval in days, and how many days an occurrence may deviate from it.
+var intervals = []struct {
+	interval  Interval
+	days      float64
+	tolerance float64
+}{
+	{IntervalWeekly, 7, 2},
+	{IntervalMonthly, 30.44, 5},
+	{IntervalYearly, 365.25, 15},
+}
+
+// Returns the date `n` intervals after `d`, keeping the day of month where possible.
+func (i Interval) After(d time.Time, n int) time.Time {
+	switch i {
+	case IntervalWeekly:
+		return d.AddDate(0, 0, 7*n)
+	case IntervalMonthly:
+		return addMonths(d, n)
+	case IntervalYearly:
+		return addMonths(d, 12*n)
+	}
+	return d
+}
+
+// Adds months to a date, clamping the day to the end of shorter months.
+func addMonths(d time.Time, n int) time.Time {
+	first := time.Date(d.Year(), d.Month()+time.Month(n), 1, 0, 0, 0, 0, d.Location())
+	last := first.AddDate(0, 1, -1).Day()
+	return first.AddDate(0, 0, min(d.Day(), last)-1)
+}
+
+// A change in the amount of a recurring transaction.
+type PriceChange struct {
+	// The date of the first occurrence with the new amount.
+	Date time.Time
+	From int
+	To   int
+}
+
+// A series of transactions to the same counterparty with similar amounts at a regular interval.
+type Recurring struct {
+	Counterparty string
+	Currency     string
+	Interval     Interval
+	// The number of occurrences found.
+	Count int
+	First time.Time
+	Last  time.Time
+	// The date the next occurrence is expected on.
+	Next time.Time
+	// The average amount in minor units.
+	Average int
+	// The number of expected occurrences that did not happen, including overdue ones.
+	Missed  int
+	Changes []PriceChange
+	// The occurrences in date order.
+	Transactions []transactions.Transaction
+}
+
+// Whether the next occurrence is still due, as opposed to the series having stopped.
+func (r Recurring) Active() bool {
+	return r.Next.Equal(r.Interval.After(r.Last, 1))
+}
+
+// Options of recurring transaction detection.
+type RecurringOptions struct {
+	// The minimum number of occurrences of a series, defaulting to 3. A series needs at least two
+	// occurrences to have an interval.
+	MinOccurrences int
+	// The relative difference up to which amounts are considered similar, defaulting to 0.2.
+	Tolerance float64
+	// The date overdue occurrences are counted up to, defaulting to the latest transaction date.
+	AsOf time.Time
+}
+
+// Finds recurring transactions, ordered by the date of their next expected occurrence.
+//
+// Transactions are grouped by counterparty, currency and direction, then split into series of
+// similar amounts, comparing each amount to the previous one of a series so that gradual price
+// changes are followed. A series is recurring if the gaps between its occurrences are whole
+// multiples of a weekly, monthly or yearly interval, where multiples count as missed occurrences,
+// and its amount changes on at most half of its occurrences.
+func FindRecurring(ts []transactions.Transaction, opts RecurringOptions) []Recurring {
+	if opts.MinOccurrences <= 0 {
+		opts.MinOccurrences = 3
+	}
+	if opts.Tolerance <= 0 {
+		opts.Tolerance = 0.2
+	}
+
+	type key struct {
+		counterparty, currency string
+		debit                  bool
+	}
+	groups := map[key][]transactions.Transaction{}
+	var keys []key
+	var latest time.Time
+	for _, t := range ts {
+		if !t.IsMovement() || t.Value == 0 {
+			continue
+		}
+		if t.Date.After(latest) {
+			latest = t.Date
+		}
+
+		k := key{strings.ToLower(strings.TrimSpace(Counterparty(t))), t.Currency, t.Value < 0}
+		if _, ok := groups[k]; !ok {
+			keys = append(keys, k)
+		}
+		groups[k] = append(groups[k], t)
+	}
+
+	if opts.AsOf.IsZero() {
+		opts.AsOf = latest
+	}
+
+	var rs []Recurring
+	for _, k := range keys {
+		g := groups[k]
+		slices.SortStableFunc(g, func(a, b transactions.Transaction) int { return a.Date.Compare(b.Date) })
+
+		for _, series := range splitByAmount(g, opts.Tolerance) {
+			if len(series) < opts.MinOccurrences {
+				continue
+			}
+			if r, ok := recurring(series, opts.AsOf); ok {
+				rs = append(rs, r)
+			}
+		}
+	}
+
+	slices.SortStableFunc(rs, func(a, b Recurring) int {
+		if c := a.Next.Compare(b.Next); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Counterparty, b.Counterparty)
+	})
+	return rs
+}
+
+// The name a transaction is grouped and reported by, which is the canonical payee if one is
+// assigned, or the counterparty name, falling back to the description.
+func Counterparty(t transactions.Transaction) string {
+	if t.Payee != "" {
+		return t.Payee
+	}
+	if t.AccountHolder != "" {
+		return t.AccountHolder
+	}
+	return t.Description
+}
+
+// Splits chronologically ordered transactions into series of similar amounts.
+func splitByAmount(ts []transactions.Transaction, tolerance float64) [][]transactions.Transaction {
+	var series [][]transactions.Transaction
+	for _, t := range ts {
+		i := slices.IndexFunc(series, func(s []transactions.Transaction) bool {
+			return similar(s[len(s)-1].Value, t.Value, tolerance)
+		})
+		if i < 0 {
+			series = append(series, []transactions.Transaction{t})
+		} else {
+			series[i] = append(series[i], t)
+		}
+	}
+	return series
+}
+
+// Whether two amounts differ by at most the relative tolerance.
+func similar(a, b int, tolerance float64) bool {
+	diff := math.Abs(float64(a - b))
+	return diff <= tolerance*math.Max(math.Abs(float64(a)), math.Abs(float64(b)))
+}
+
+// Checks whether a series occurs at a regular interval and describes it if so.
+func recurring(series []transactions.Transaction, asOf time.Time) (Recurring, bool) {
+	if len(series) < 2 {
+		return Recurring{}, false
+	}
+	gaps := make([]float64, len(series)-1)
+	for i := 1; i < len(series); i++ {
+		gaps[i-1] = series[i].Date.Sub(series[i-1].Date).Hours() / 24
+	}
+	median := slices.Clone(gaps)
+	slices.Sort(median)
+
+	for _, iv := range intervals {
+		if math.Abs(median[len(median)/2]-iv.days) > iv.tolerance {
+			continue
+		}
+
+		missed := 0
+		regular := true
+		for _, gap := range gaps {
+			n := math.Round(gap / iv.days)
+			if n < 1 || math.Abs(gap-n*iv.days) > iv.tolerance*n {
+				regular = false
+				break
+			}
+			missed += int(n) - 1
+		}
+		if !regular {
+			return Recurring{}, false
+		}
+
+		first, last := series[0], series[len(series)-1]
+		r := Recurring{
+			Counterparty: Counterparty(last),
+			Currency:     last.Currency,
+			Interval:     iv.interval,
+			Count:        len(series),
+			First:        first.Date,
+			Last:         last.Date,
+			Next:         iv.interval.After(last.Date, 1),
+			Transactions: series,
+		}
+
+		// Occurrences are overdue once the tolerance has passed without them.
+		for n := 1; iv.interval.After(last.Date, n).AddDate(0, 0, int(iv.tolerance)).Before(asOf); n++ {
+			missed++
+			r.Next = iv.interval.After(last.Date, n+1)
+		}
+		r.Missed = missed
+
+		sum := 0
+		for i, t := range series {
+			sum += t.Value
+			if i > 0 && t.Value != series[i-1].Value {
+				r.Changes = append(r.Changes, PriceChange{Date: t.Date, From: series[i-1].Value, To: t.Value})
+			}
+		}
+		r.Average = int(math.Round(float64(sum) / float64(len(series))))
+
+		// Amounts changing on most occurrences point to coincidental purchases of similar value.
+		if 2*len(r.Changes) > len(series)-1 {
+			return Recurring{}, false
+		}
+
+		return r, true
+	}
+
+	return Recurring{}, false
+}
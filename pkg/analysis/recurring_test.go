@@ -0,0 +1,95 @@
+package analysis_test
+
+import (
+	"statements/pkg/analysis"
+	"statements/pkg/transactions"
+	"testing"
+	"time"
+)
+
+func monthly(holder string, day int, values ...int) []transactions.Transaction {
+	var ts []transactions.Transaction
+	for i, v := range values {
+		if v == 0 {
+			continue
+		}
+		ts = append(ts, transactions.Transaction{
+			Date:          time.Date(2025, time.Month(i+1), day, 0, 0, 0, 0, time.UTC),
+			AccountHolder: holder,
+			Value:         v,
+			Currency:      "EUR",
+		})
+	}
+	return ts
+}
+
+func TestFindRecurring(t *testing.T) {
+	ts := monthly("Streaming", 28, -999, -999, -999, -1149, -1149, -1149)
+	ts = append(ts, monthly("Music", 10, -1099, -1099, 0, -1099, -1099)...)
+	ts = append(ts, monthly("Shop", 3, -2000, -4500)...)
+
+	rs := analysis.FindRecurring(ts, analysis.RecurringOptions{})
+	if len(rs) != 2 {
+		t.Fatalf("FindRecurring() found %d series, want 2: %+v", len(rs), rs)
+	}
+
+	music := rs[0]
+	if music.Counterparty != "Music" || music.Interval != analysis.IntervalMonthly {
+		t.Errorf("first series = %s %s, want monthly Music", music.Interval, music.Counterparty)
+	}
+	// The March payment is missing and the June one is overdue as of the last transaction.
+	if music.Missed != 2 || !music.Next.Equal(time.Date(2025, 7, 10, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Music missed %d, next %v, want 2 missed and next on 2025-07-10", music.Missed, music.Next)
+	}
+
+	streaming := rs[1]
+	if streaming.Count != 6 || streaming.Average != -1074 {
+		t.Errorf("Streaming count %d, average %d, want 6 and -1074", streaming.Count, streaming.Average)
+	}
+	if !streaming.Next.Equal(time.Date(2025, 7, 28, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Streaming next = %v, want 2025-07-28", streaming.Next)
+	}
+	if len(streaming.Changes) != 1 || streaming.Changes[0].From != -999 || streaming.Changes[0].To != -1149 {
+		t.Errorf("Streaming changes = %+v, want a single change from -999 to -1149", streaming.Changes)
+	}
+}
+
+func TestFindRecurringIrregular(t *testing.T) {
+	day := func(m time.Month, d int) time.Time { return time.Date(2025, m, d, 0, 0, 0, 0, time.UTC) }
+	ts := []transactions.Transaction{
+		{Date: day(1, 1), AccountHolder: "Cafe", Value: -500},
+		{Date: day(1, 3), AccountHolder: "Cafe", Value: -500},
+		{Date: day(2, 20), AccountHolder: "Cafe", Value: -500},
+		{Date: day(3, 1), AccountHolder: "Cafe", Value: -500},
+	}
+
+	if rs := analysis.FindRecurring(ts, analysis.RecurringOptions{}); len(rs) != 0 {
+		t.Errorf("FindRecurring() = %+v, want no series for irregular payments", rs)
+	}
+}
+
+func TestFindRecurringSingleOccurrence(t *testing.T) {
+	ts := monthly("Music", 10, -1099)
+	if rs := analysis.FindRecurring(ts, analysis.RecurringOptions{MinOccurrences: 1}); len(rs) != 0 {
+		t.Errorf("FindRecurring() = %+v, want no series of a single payment", rs)
+	}
+}
+
+func TestIntervalAfter(t *testing.T) {
+	d := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		interval analysis.Interval
+		n        int
+		want     time.Time
+	}{
+		{analysis.IntervalWeekly, 2, time.Date(2024, 2, 14, 0, 0, 0, 0, time.UTC)},
+		{analysis.IntervalMonthly, 1, time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{analysis.IntervalMonthly, 2, time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC)},
+		{analysis.IntervalYearly, 1, time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		if got := tt.interval.After(d, tt.n); !got.Equal(tt.want) {
+			t.Errorf("%s.After(%d) = %v, want %v", tt.interval, tt.n, got, tt.want)
+		}
+	}
+}
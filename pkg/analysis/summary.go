@@ -0,0 +1,182 @@
+package analysis
+
+import (
+	"fmt"
+	"slices"
+	"statements/pkg/transactions"
+	"strings"
+	"time"
+)
+
+// The dimension transactions are grouped by in a summary.
+type Grouping string
+
+const (
+	GroupDay          Grouping = "day"
+	GroupWeek         Grouping = "week"
+	GroupMonth        Grouping = "month"
+	GroupQuarter      Grouping = "quarter"
+	GroupYear         Grouping = "year"
+	GroupCounterparty Grouping = "counterparty"
+	GroupCategory     Grouping = "category"
+	GroupTag          Grouping = "tag"
+)
+
+// The supported groupings, ordered from the finest period to the other dimensions.
+var Groupings = []Grouping{
+	GroupDay,
+	GroupWeek,
+	GroupMonth,
+	GroupQuarter,
+	GroupYear,
+	GroupCounterparty,
+	GroupCategory,
+	GroupTag,
+}
+
+// Parses a string into a grouping.
+func ParseGrouping(v string) (Grouping, error) {
+	g := Grouping(strings.ToLower(v))
+	if !slices.Contains(Groupings, g) {
+		return "", fmt.Errorf("unknown grouping %q, must be one of %v", v, Groupings)
+	}
+	return g, nil
+}
+
+// The key of the group a transaction belongs to, which is its first tag when grouping by tag.
+//
+// Period keys sort chronologically, such as `2025-01-31`, `2025-W05`, `2025-01`, `2025-Q1` and
+// `2025`, with weeks following ISO 8601.
+func (g Grouping) Key(t transactions.Transaction) string {
+	switch g {
+	case GroupDay:
+		return t.Date.Format(time.DateOnly)
+	case GroupWeek:
+		year, week := t.Date.ISOWeek()
+		return fmt.Sprintf("%04d-W%02d", year, week)
+	case GroupMonth:
+		return t.Date.Format("2006-01")
+	case GroupQuarter:
+		return fmt.Sprintf("%04d-Q%d", t.Date.Year(), (int(t.Date.Month())+2)/3)
+	case GroupYear:
+		return t.Date.Format("2006")
+	case GroupCounterparty:
+		if t.Payee != "" {
+			return t.Payee
+		}
+		if t.AccountHolder == "" {
+			return "Unknown"
+		}
+		return t.AccountHolder
+	case GroupCategory:
+		if t.Category == "" {
+			return "Uncategorized"
+		}
+		return t.Category
+	case GroupTag:
+		if len(t.Tags) == 0 {
+			return "Untagged"
+		}
+		return t.Tags[0]
+	}
+	return ""
+}
+
+// The keys of all groups a transaction belongs to, which is one group per tag when grouping by
+// tag and a single group otherwise.
+func (g Grouping) Keys(t transactions.Transaction) []string {
+	if g == GroupTag && len(t.Tags) > 1 {
+		return t.Tags
+	}
+	return []string{g.Key(t)}
+}
+
+// Aggregated figures of a set of transactions in a single currency, in minor units.
+type Totals struct {
+	// The sum of all credits.
+	Income int
+	// The sum of all debits, as a negative number.
+	Spending int
+	// The number of transactions.
+	Count int
+}
+
+// The difference between income and spending.
+func (t Totals) Net() int {
+	return t.Income + t.Spending
+}
+
+// Adds a transaction to the totals.
+func (t *Totals) Add(v int) {
+	t.Count++
+	if v < 0 {
+		t.Spending += v
+	} else {
+		t.Income += v
+	}
+}
+
+// The totals of a single group and currency.
+type Group struct {
+	Key      string
+	Currency string
+	Totals
+}
+
+// Aggregates transactions into groups, keeping currencies apart.
+type Summary struct {
+	By     Grouping
+	groups []Group
+	idx    map[[2]string]int
+}
+
+// Creates an empty summary grouped by the provided dimension.
+func NewSummary(by Grouping) *Summary {
+	return &Summary{By: by, idx: map[[2]string]int{}}
+}
+
+// Adds a transaction to its groups. Statement rows that do not move money are ignored.
+//
+// Split transactions are added as their split lines. A transaction with several tags is added to
+// the group of each, so tag groups can add up to more than the transactions they contain.
+func (s *Summary) Add(t transactions.Transaction) {
+	if !t.IsMovement() {
+		return
+	}
+
+	for _, l := range t.Lines() {
+		for _, k := range s.By.Keys(l) {
+			key := [2]string{k, l.Currency}
+			i, ok := s.idx[key]
+			if !ok {
+				i = len(s.groups)
+				s.idx[key] = i
+				s.groups = append(s.groups, Group{Key: key[0], Currency: key[1]})
+			}
+			s.groups[i].Add(l.Value)
+		}
+	}
+}
+
+// Adds all transactions of a stream to their groups.
+func (s *Summary) AddAll(seq transactions.Seq) error {
+	for t, err := range seq {
+		if err != nil {
+			return err
+		}
+		s.Add(t)
+	}
+	return nil
+}
+
+// The groups of the summary, ordered by key and currency.
+func (s *Summary) Groups() []Group {
+	gs := slices.Clone(s.groups)
+	slices.SortFunc(gs, func(a, b Group) int {
+		if c := strings.Compare(a.Key, b.Key); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Currency, b.Currency)
+	})
+	return gs
+}
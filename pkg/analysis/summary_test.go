@@ -0,0 +1,128 @@
+package analysis_test
+
+import (
+	"statements/pkg/analysis"
+	"statements/pkg/transactions"
+	"testing"
+	"time"
+)
+
+func TestGroupingKey(t *testing.T) {
+	tr := transactions.Transaction{
+		Date:          time.Date(2025, 12, 30, 0, 0, 0, 0, time.UTC),
+		AccountHolder: "Shop",
+	}
+
+	tests := []struct {
+		by   analysis.Grouping
+		want string
+	}{
+		{analysis.GroupDay, "2025-12-30"},
+		{analysis.GroupWeek, "2026-W01"},
+		{analysis.GroupMonth, "2025-12"},
+		{analysis.GroupQuarter, "2025-Q4"},
+		{analysis.GroupYear, "2025"},
+		{analysis.GroupCounterparty, "Shop"},
+		{analysis.GroupCategory, "Uncategorized"},
+		{analysis.GroupTag, "Untagged"},
+	}
+	for _, tt := range tests {
+		if got := tt.by.Key(tr); got != tt.want {
+			t.Errorf("%s key = %q, want %q", tt.by, got, tt.want)
+		}
+	}
+
+	tr.Payee = "Shop Ltd"
+	if got := analysis.GroupCounterparty.Key(tr); got != "Shop Ltd" {
+		t.Errorf("counterparty key = %q, want the payee Shop Ltd", got)
+	}
+}
+
+func TestParseGrouping(t *testing.T) {
+	if g, err := analysis.ParseGrouping("Quarter"); err != nil || g != analysis.GroupQuarter {
+		t.Errorf("ParseGrouping(Quarter) = %q, %v, want quarter", g, err)
+	}
+	if _, err := analysis.ParseGrouping("weekday"); err == nil {
+		t.Error("ParseGrouping(weekday) expected an error")
+	}
+}
+
+func TestSummary(t *testing.T) {
+	jan := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+	feb := time.Date(2025, 2, 10, 0, 0, 0, 0, time.UTC)
+	ts := []transactions.Transaction{
+		{Date: feb, Value: -500, Currency: "EUR"},
+		{Date: jan, Value: 10000, Currency: "EUR"},
+		{Date: jan, Value: -2500, Currency: "EUR"},
+		{Date: jan, Value: -100, Currency: "USD"},
+		{Date: jan, Value: 7400, Currency: "EUR", Kind: transactions.KindClosingBalance},
+	}
+
+	s := analysis.NewSummary(analysis.GroupMonth)
+	if err := s.AddAll(transactions.All(ts)); err != nil {
+		t.Fatalf("AddAll() unexpected error: %v", err)
+	}
+
+	gs := s.Groups()
+	want := []struct {
+		key, currency                string
+		income, spending, net, count int
+	}{
+		{"2025-01", "EUR", 10000, -2500, 7500, 2},
+		{"2025-01", "USD", 0, -100, -100, 1},
+		{"2025-02", "EUR", 0, -500, -500, 1},
+	}
+	if len(gs) != len(want) {
+		t.Fatalf("summary has %d groups, want %d", len(gs), len(want))
+	}
+	for i, w := range want {
+		g := gs[i]
+		if g.Key != w.key || g.Currency != w.currency || g.Income != w.income || g.Spending != w.spending || g.Net() != w.net || g.Count != w.count {
+			t.Errorf("group %d = %+v (net %d), want %+v", i, g, g.Net(), w)
+		}
+	}
+}
+
+func TestSummary_ByTag(t *testing.T) {
+	date := time.Date(2025, 7, 3, 0, 0, 0, 0, time.UTC)
+	ts := []transactions.Transaction{
+		{Date: date, Value: -12000, Currency: "EUR", Tags: []string{"vacation-2025", "reimbursable"}},
+		{Date: date, Value: -3000, Currency: "EUR", Tags: []string{"vacation-2025"}},
+		{Date: date, Value: -500, Currency: "EUR"},
+	}
+
+	s := analysis.NewSummary(analysis.GroupTag)
+	if err := s.AddAll(transactions.All(ts)); err != nil {
+		t.Fatalf("AddAll() unexpected error: %v", err)
+	}
+
+	want := map[string]int{"reimbursable": -12000, "Untagged": -500, "vacation-2025": -15000}
+	gs := s.Groups()
+	if len(gs) != len(want) {
+		t.Fatalf("summary has groups %+v, want one per tag and untagged", gs)
+	}
+	for _, g := range gs {
+		if g.Spending != want[g.Key] {
+			t.Errorf("group %s spending = %d, want %d", g.Key, g.Spending, want[g.Key])
+		}
+	}
+}
+
+func TestSummary_SplitLines(t *testing.T) {
+	s := analysis.NewSummary(analysis.GroupCategory)
+	s.Add(transactions.Transaction{
+		Date:     time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+		Value:    -5161,
+		Currency: "EUR",
+		Category: "Groceries",
+		Splits: []transactions.Split{
+			{Category: "Groceries", Value: -3861},
+			{Category: "Household", Value: -1300},
+		},
+	})
+
+	gs := s.Groups()
+	if len(gs) != 2 || gs[0].Key != "Groceries" || gs[0].Spending != -3861 || gs[1].Key != "Household" || gs[1].Spending != -1300 {
+		t.Errorf("summary groups = %+v, want a group per split", gs)
+	}
+}
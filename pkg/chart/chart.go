@@ -0,0 +1,189 @@
+package chart
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+// The characters charts are drawn with.
+type Charset struct {
+	// Bar segments of increasing width, the last one filling a whole cell.
+	Bar []string
+	// Sparkline levels of increasing height.
+	Spark []rune
+	// The marker of a line chart point, and the character connecting points vertically.
+	Point, Vertical rune
+	// The axis characters of line charts.
+	AxisY, AxisX, Corner rune
+}
+
+var Unicode = Charset{
+	Bar:      []string{"▏", "▎", "▍", "▌", "▋", "▊", "▉", "█"},
+	Spark:    []rune("▁▂▃▄▅▆▇█"),
+	Point:    '•',
+	Vertical: '│',
+	AxisY:    '┤',
+	AxisX:    '─',
+	Corner:   '└',
+}
+
+var ASCII = Charset{
+	Bar:      []string{"#"},
+	Spark:    []rune("_.-=*#"),
+	Point:    '*',
+	Vertical: '|',
+	AxisY:    '|',
+	AxisX:    '-',
+	Corner:   '+',
+}
+
+// Chooses the charset supported by the terminal, based on the locale environment variables.
+func Detect() Charset {
+	for _, env := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if v := os.Getenv(env); v != "" {
+			v = strings.ToLower(v)
+			if strings.Contains(v, "utf-8") || strings.Contains(v, "utf8") {
+				return Unicode
+			}
+			return ASCII
+		}
+	}
+	return ASCII
+}
+
+// A labeled value of a bar chart, with the text printed after the bar.
+type Bar struct {
+	Label string
+	Value float64
+	Text  string
+}
+
+// Draws a horizontal bar chart fitting into `width` columns.
+//
+// Bars are scaled to the largest absolute value, so negative values are drawn by their size.
+func Bars(w io.Writer, bars []Bar, width int, cs Charset) error {
+	labelWidth, textWidth, largest := 0, 0, 0.0
+	for _, b := range bars {
+		labelWidth = max(labelWidth, utf8.RuneCountInString(b.Label))
+		textWidth = max(textWidth, utf8.RuneCountInString(b.Text))
+		largest = max(largest, math.Abs(b.Value))
+	}
+	barWidth := max(width-labelWidth-textWidth-2, 10)
+
+	for _, b := range bars {
+		size := 0.0
+		if largest > 0 {
+			size = math.Abs(b.Value) / largest * float64(barWidth)
+		}
+		bar := bar(size, cs)
+		_, err := fmt.Fprintf(w, "%s %s%s %s\n", pad(b.Label, labelWidth), bar,
+			strings.Repeat(" ", barWidth-utf8.RuneCountInString(bar)), b.Text)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Draws a bar of a fractional number of cells, using partial segments where available.
+func bar(size float64, cs Charset) string {
+	full := int(size)
+	s := strings.Repeat(cs.Bar[len(cs.Bar)-1], full)
+	steps := len(cs.Bar)
+	if part := int((size - float64(full)) * float64(steps)); part > 0 && steps > 1 {
+		s += cs.Bar[part-1]
+	}
+	return s
+}
+
+// Draws values as a single line of increasing levels, scaled between their minimum and maximum.
+func Sparkline(values []float64, cs Charset) string {
+	if len(values) == 0 {
+		return ""
+	}
+	lo, hi := values[0], values[0]
+	for _, v := range values {
+		lo, hi = min(lo, v), max(hi, v)
+	}
+
+	levels := len(cs.Spark)
+	var b strings.Builder
+	for _, v := range values {
+		i := 0
+		if hi > lo {
+			i = int((v - lo) / (hi - lo) * float64(levels-1))
+		}
+		b.WriteRune(cs.Spark[i])
+	}
+	return b.String()
+}
+
+// Draws values as a line chart of `height` rows fitting into `width` columns.
+//
+// Values are resampled to the available columns, labeled with their range on the vertical axis
+// and with `first` and `last` below the horizontal axis.
+func Line(w io.Writer, values []float64, first, last string, width, height int, cs Charset) error {
+	if len(values) == 0 {
+		return nil
+	}
+	height = max(height, 2)
+	lo, hi := values[0], values[0]
+	for _, v := range values {
+		lo, hi = min(lo, v), max(hi, v)
+	}
+
+	labels := make([]string, height)
+	labelWidth := 0
+	for r := range labels {
+		labels[r] = fmt.Sprintf("%.2f", hi-(hi-lo)*float64(r)/float64(height-1))
+		labelWidth = max(labelWidth, len(labels[r]))
+	}
+	cols := max(width-labelWidth-1, 10)
+
+	// The row of each column, with row 0 at the top.
+	rows := make([]int, cols)
+	for c := range cols {
+		v := values[c*(len(values)-1)/max(cols-1, 1)]
+		if hi > lo {
+			rows[c] = int(math.Round((hi - v) / (hi - lo) * float64(height-1)))
+		}
+	}
+
+	grid := make([][]rune, height)
+	for r := range grid {
+		grid[r] = []rune(strings.Repeat(" ", cols))
+	}
+	for c, r := range rows {
+		if c > 0 {
+			// Connects steps to the previous column so the line stays continuous.
+			from, to := min(r, rows[c-1]), max(r, rows[c-1])
+			for between := from + 1; between < to; between++ {
+				grid[between][c] = cs.Vertical
+			}
+		}
+		grid[r][c] = cs.Point
+	}
+
+	for r, line := range grid {
+		label := ""
+		if r == 0 || r == height-1 || r == height/2 {
+			label = labels[r]
+		}
+		if _, err := fmt.Fprintf(w, "%*s%c%s\n", labelWidth, label, cs.AxisY, strings.TrimRight(string(line), " ")); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(w, "%*s%c%s\n", labelWidth, "", cs.Corner, strings.Repeat(string(cs.AxisX), cols))
+	gap := max(cols-utf8.RuneCountInString(first)-utf8.RuneCountInString(last), 1)
+	_, err := fmt.Fprintf(w, "%*s %s%s%s\n", labelWidth, "", first, strings.Repeat(" ", gap), last)
+	return err
+}
+
+// Pads a string with spaces to a number of runes.
+func pad(s string, width int) string {
+	return s + strings.Repeat(" ", max(width-utf8.RuneCountInString(s), 0))
+}
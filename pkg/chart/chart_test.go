@@ -0,0 +1,80 @@
+package chart_test
+
+import (
+	"statements/pkg/chart"
+	"strings"
+	"testing"
+)
+
+func TestSparkline(t *testing.T) {
+	tests := []struct {
+		values []float64
+		cs     chart.Charset
+		want   string
+	}{
+		{nil, chart.ASCII, ""},
+		{[]float64{1, 1, 1}, chart.ASCII, "___"},
+		{[]float64{0, 5, 10}, chart.ASCII, "_-#"},
+		{[]float64{0, 7, 3.5}, chart.Unicode, "▁█▄"},
+	}
+
+	for _, tt := range tests {
+		if got := chart.Sparkline(tt.values, tt.cs); got != tt.want {
+			t.Errorf("Sparkline(%v) = %q, want %q", tt.values, got, tt.want)
+		}
+	}
+}
+
+func TestBars(t *testing.T) {
+	bars := []chart.Bar{
+		{Label: "Groceries", Value: 200, Text: "200.00"},
+		{Label: "Fun", Value: -100, Text: "-100.00"},
+		{Label: "Empty", Value: 0, Text: "0.00"},
+	}
+
+	var b strings.Builder
+	if err := chart.Bars(&b, bars, 40, chart.ASCII); err != nil {
+		t.Fatal(err)
+	}
+	want := "Groceries ###################### 200.00\n" +
+		"Fun       ###########            -100.00\n" +
+		"Empty                            0.00\n"
+	if got := b.String(); got != want {
+		t.Errorf("Bars() =\n%s\nwant\n%s", got, want)
+	}
+
+	b.Reset()
+	if err := chart.Bars(&b, bars[:1], 24, chart.Unicode); err != nil {
+		t.Fatal(err)
+	}
+	if got := b.String(); !strings.HasPrefix(got, "Groceries ██████████ 200.00") {
+		t.Errorf("Bars() = %q, want a full unicode bar", got)
+	}
+}
+
+func TestLine(t *testing.T) {
+	var b strings.Builder
+	err := chart.Line(&b, []float64{0, 1, 2, 3}, "first", "last", 14, 4, chart.ASCII)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := strings.Split(b.String(), "\n")
+	want := []string{
+		"3.00|         *",
+		"    |      ***",
+		"1.00|   ***",
+		"0.00|***",
+		"    +----------",
+		"     first last",
+		"",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Line() =\n%s\nwant %d lines", b.String(), len(want)-1)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Line() line %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
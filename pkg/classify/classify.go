@@ -0,0 +1,62 @@
+package classify
+
+import (
+	"fmt"
+	"statements/pkg/config"
+	"statements/pkg/transactions"
+)
+
+// A category rule with its filters decoded.
+type rule struct {
+	category string
+	filters  []config.Filter
+}
+
+// Assigns categories to normalized transactions based on the configured rules.
+type Classifier struct {
+	rules []rule
+}
+
+// Creates a classifier from the category rules in the configuration.
+func NewClassifier(crs []config.CategoryRule) (*Classifier, error) {
+	c := &Classifier{}
+	for i, cr := range crs {
+		fs, err := transactions.DecodeFilters(cr.Filters)
+		if err != nil {
+			return nil, fmt.Errorf("category rule %d: %v", i+1, err)
+		}
+		c.rules = append(c.rules, rule{category: cr.Category, filters: fs})
+	}
+	return c, nil
+}
+
+// Assigns the category of the first matching rule to a transaction.
+//
+// Transactions that already have a category, or that do not move money, are left unchanged.
+func (c *Classifier) Classify(t transactions.Transaction) transactions.Transaction {
+	if t.Category != "" || !t.IsMovement() {
+		return t
+	}
+	for _, r := range c.rules {
+		if t.Matches(r.filters) {
+			t.Category = r.category
+			break
+		}
+	}
+	return t
+}
+
+// Classifies a stream of transactions.
+func (c *Classifier) Apply(seq transactions.Seq) transactions.Seq {
+	return func(yield func(transactions.Transaction, error) bool) {
+		for t, err := range seq {
+			if err != nil {
+				yield(t, err)
+				return
+			}
+			if !yield(c.Classify(t), nil) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package classify_test
+
+import (
+	"encoding/json"
+	"statements/pkg/classify"
+	"statements/pkg/config"
+	"statements/pkg/transactions"
+	"testing"
+)
+
+func parseRules(t *testing.T, data string) []config.CategoryRule {
+	var crs []config.CategoryRule
+	if err := json.Unmarshal([]byte(data), &crs); err != nil {
+		t.Fatalf("Failed to parse category rules: %v", err)
+	}
+	return crs
+}
+
+func TestClassifier_Classify(t *testing.T) {
+	crs := parseRules(t, `[
+		{"category": "Groceries", "filters": [{"field": "accountHolder", "condition": "CONTAIN", "comparison": "MAXIMA"}]},
+		{"category": "Large", "filters": [{"field": "value", "condition": "LESS_THAN", "comparison": -100}]},
+		{"category": "Card", "filters": [{"field": "metadata.flow", "condition": "EQUAL", "comparison": "D"}]}
+	]`)
+
+	c, err := classify.NewClassifier(crs)
+	if err != nil {
+		t.Fatalf("NewClassifier() unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		t    transactions.Transaction
+		want string
+	}{
+		{
+			name: "first matching rule wins",
+			t:    transactions.Transaction{AccountHolder: "MAXIMA LV", Value: -50000},
+			want: "Groceries",
+		},
+		{
+			name: "number filter on major units",
+			t:    transactions.Transaction{AccountHolder: "IKEA", Value: -15000},
+			want: "Large",
+		},
+		{
+			name: "metadata field",
+			t:    transactions.Transaction{Value: -100, Metadata: map[string]string{"flow": "D"}},
+			want: "Card",
+		},
+		{
+			name: "no match",
+			t:    transactions.Transaction{AccountHolder: "IKEA", Value: -100},
+			want: "",
+		},
+		{
+			name: "existing category is kept",
+			t:    transactions.Transaction{AccountHolder: "MAXIMA LV", Category: "Gifts"},
+			want: "Gifts",
+		},
+		{
+			name: "balances are not classified",
+			t:    transactions.Transaction{Kind: transactions.KindClosingBalance, AccountHolder: "MAXIMA LV"},
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.Classify(tt.t).Category; got != tt.want {
+				t.Errorf("Classify().Category = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewClassifier_UnknownField(t *testing.T) {
+	crs := parseRules(t, `[{"category": "X", "filters": [{"field": "Summa", "condition": "EQUAL", "comparison": 1}]}]`)
+	if _, err := classify.NewClassifier(crs); err == nil {
+		t.Error("NewClassifier() expected error but got none")
+	}
+}
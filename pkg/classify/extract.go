@@ -0,0 +1,96 @@
+package classify
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"statements/pkg/config"
+	"statements/pkg/transactions"
+	"strings"
+)
+
+// An extraction rule with its pattern compiled.
+type extractRule struct {
+	field   string
+	re      *regexp.Regexp
+	rewrite string
+}
+
+// Extracts fields from normalized transactions into metadata based on the configured rules.
+type FieldExtractor struct {
+	rules []extractRule
+}
+
+// Creates a field extractor from the extraction rules in the configuration.
+func NewFieldExtractor(ers []config.ExtractRule) (*FieldExtractor, error) {
+	e := &FieldExtractor{}
+	for i, er := range ers {
+		field := er.Field
+		if field == "" {
+			field = "description"
+		}
+		if transactions.FieldTypeOf(field) != config.FieldTypeString {
+			return nil, fmt.Errorf("extraction rule %d: %q is not a string field", i+1, field)
+		}
+		re, err := regexp.Compile(er.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("extraction rule %d: invalid pattern: %v", i+1, err)
+		}
+		e.rules = append(e.rules, extractRule{field: field, re: re, rewrite: er.Rewrite})
+	}
+	return e, nil
+}
+
+// Applies every matching rule to a transaction in order, so later rules see the fields
+// extracted and the description rewritten by earlier ones.
+//
+// Named groups that matched are stored as metadata fields, replacing existing values.
+// Transactions that do not move money are left unchanged.
+func (e *FieldExtractor) Extract(t transactions.Transaction) transactions.Transaction {
+	if !t.IsMovement() {
+		return t
+	}
+	copied := false
+	for _, r := range e.rules {
+		s, _ := t.FieldValue(r.field).(string)
+		m := r.re.FindStringSubmatchIndex(s)
+		if m == nil {
+			continue
+		}
+
+		for i, name := range r.re.SubexpNames() {
+			if name == "" || m[2*i] < 0 || m[2*i] == m[2*i+1] {
+				continue
+			}
+			// The metadata map may be shared with other copies of the transaction.
+			if !copied {
+				t.Metadata = maps.Clone(t.Metadata)
+				if t.Metadata == nil {
+					t.Metadata = map[string]string{}
+				}
+				copied = true
+			}
+			t.Metadata[name] = s[m[2*i]:m[2*i+1]]
+		}
+		if r.rewrite != "" {
+			d := r.re.ExpandString(nil, r.rewrite, s, m)
+			t.Description = strings.Join(strings.Fields(string(d)), " ")
+		}
+	}
+	return t
+}
+
+// Extracts the fields of a stream of transactions.
+func (e *FieldExtractor) Apply(seq transactions.Seq) transactions.Seq {
+	return func(yield func(transactions.Transaction, error) bool) {
+		for t, err := range seq {
+			if err != nil {
+				yield(t, err)
+				return
+			}
+			if !yield(e.Extract(t), nil) {
+				return
+			}
+		}
+	}
+}
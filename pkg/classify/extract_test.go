@@ -0,0 +1,103 @@
+package classify_test
+
+import (
+	"maps"
+	"statements/pkg/classify"
+	"statements/pkg/config"
+	"statements/pkg/transactions"
+	"testing"
+)
+
+func TestFieldExtractor_Extract(t *testing.T) {
+	e, err := classify.NewFieldExtractor([]config.ExtractRule{
+		{
+			Pattern: `^PIRKUMS (?P<card>\d{4}\*+\d{4}) (?P<merchant>.+?)(?: (?P<city>RIGA|VILNIUS))?$`,
+			Rewrite: "$merchant",
+		},
+		{Pattern: `(?P<originalAmount>\d+\.\d{2}) (?P<originalCurrency>[A-Z]{3})$`},
+		{Field: "reference", Pattern: `^INV-(?P<invoice>\d+)$`},
+	})
+	if err != nil {
+		t.Fatalf("NewFieldExtractor() unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name            string
+		t               transactions.Transaction
+		wantDescription string
+		wantMetadata    map[string]string
+	}{
+		{
+			name:            "named groups and rewrite",
+			t:               transactions.Transaction{Description: "PIRKUMS 4111***1234 MAXIMA X45 RIGA"},
+			wantDescription: "MAXIMA X45",
+			wantMetadata:    map[string]string{"card": "4111***1234", "merchant": "MAXIMA X45", "city": "RIGA"},
+		},
+		{
+			name:            "optional groups that did not match are skipped",
+			t:               transactions.Transaction{Description: "PIRKUMS 4111***1234 Amazon.de"},
+			wantDescription: "Amazon.de",
+			wantMetadata:    map[string]string{"card": "4111***1234", "merchant": "Amazon.de"},
+		},
+		{
+			name:            "later rules match the rewritten description",
+			t:               transactions.Transaction{Description: "PIRKUMS 4111***1234 STEAM 12.50 USD"},
+			wantDescription: "STEAM 12.50 USD",
+			wantMetadata: map[string]string{
+				"card": "4111***1234", "merchant": "STEAM 12.50 USD", "originalAmount": "12.50", "originalCurrency": "USD",
+			},
+		},
+		{
+			name:            "other fields",
+			t:               transactions.Transaction{Description: "Invoice", Reference: "INV-2025", Metadata: map[string]string{"source": "a.csv"}},
+			wantDescription: "Invoice",
+			wantMetadata:    map[string]string{"source": "a.csv", "invoice": "2025"},
+		},
+		{
+			name:            "no match",
+			t:               transactions.Transaction{Description: "Rent"},
+			wantDescription: "Rent",
+		},
+		{
+			name:            "balances are left unchanged",
+			t:               transactions.Transaction{Kind: transactions.KindClosingBalance, Description: "PIRKUMS 4111***1234 MAXIMA"},
+			wantDescription: "PIRKUMS 4111***1234 MAXIMA",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := e.Extract(tt.t)
+			if got.Description != tt.wantDescription {
+				t.Errorf("Extract().Description = %q, want %q", got.Description, tt.wantDescription)
+			}
+			if !maps.Equal(got.Metadata, tt.wantMetadata) {
+				t.Errorf("Extract().Metadata = %v, want %v", got.Metadata, tt.wantMetadata)
+			}
+		})
+	}
+}
+
+func TestFieldExtractor_KeepsSharedMetadata(t *testing.T) {
+	e, err := classify.NewFieldExtractor([]config.ExtractRule{{Pattern: `(?P<word>\w+)`}})
+	if err != nil {
+		t.Fatalf("NewFieldExtractor() unexpected error: %v", err)
+	}
+
+	original := transactions.Transaction{Description: "Rent", Metadata: map[string]string{"source": "a.csv"}}
+	e.Extract(original)
+	if _, ok := original.Metadata["word"]; ok {
+		t.Error("Extract() modified the metadata of the original transaction")
+	}
+}
+
+func TestNewFieldExtractor_Invalid(t *testing.T) {
+	for _, er := range []config.ExtractRule{
+		{Pattern: "("},
+		{Field: "value", Pattern: "1"},
+	} {
+		if _, err := classify.NewFieldExtractor([]config.ExtractRule{er}); err == nil {
+			t.Errorf("NewFieldExtractor(%+v) expected error but got none", er)
+		}
+	}
+}
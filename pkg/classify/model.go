@@ -0,0 +1,242 @@
+package classify
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"slices"
+	"statements/pkg/config"
+	"statements/pkg/transactions"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// The model file used if none is configured.
+const DefaultModel = "model.json"
+
+// The confidence from which suggestions are applied if no threshold is configured.
+const DefaultThreshold = 0.9
+
+// A naive Bayes text model predicting categories from labeled transactions.
+//
+// Transactions are described by their payee, the words of their payee and description, and the
+// size of their amount. The model is stored as JSON, so it can be trained once and shared.
+type Model struct {
+	Categories map[string]*ModelCategory `json:"categories"`
+
+	// The features seen in any category, computed on the first suggestion after training.
+	vocabulary map[string]bool
+}
+
+// The feature counts of the transactions labeled with a category.
+type ModelCategory struct {
+	// The number of transactions.
+	Documents int `json:"documents"`
+	// The number of transactions each feature occurs in.
+	Features map[string]int `json:"features"`
+	// The sum of all feature counts.
+	Total int `json:"total"`
+}
+
+// A category proposed by a model, with the probability it is right.
+type Suggestion struct {
+	Category   string
+	Confidence float64
+}
+
+// Creates an empty model.
+func NewModel() *Model {
+	return &Model{Categories: map[string]*ModelCategory{}}
+}
+
+// Reads a model written by `Save`.
+func LoadModel(path string) (*Model, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read model: %v", err)
+	}
+	m := NewModel()
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("could not parse model %s: %v", path, err)
+	}
+	return m, nil
+}
+
+// Writes the model as JSON.
+func (m *Model) Save(path string) error {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return fmt.Errorf("could not encode model: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("could not write model: %v", err)
+	}
+	return nil
+}
+
+// Learns from a transaction labeled with a category, ignoring unlabeled ones.
+func (m *Model) Train(t transactions.Transaction) {
+	if t.Category == "" {
+		return
+	}
+	c, ok := m.Categories[t.Category]
+	if !ok {
+		c = &ModelCategory{Features: map[string]int{}}
+		m.Categories[t.Category] = c
+	}
+	c.Documents++
+	m.vocabulary = nil
+	for _, f := range Features(t) {
+		c.Features[f]++
+		c.Total++
+	}
+}
+
+// The number of transactions the model was trained on.
+func (m *Model) Documents() int {
+	n := 0
+	for _, c := range m.Categories {
+		n += c.Documents
+	}
+	return n
+}
+
+// Proposes categories for a transaction, most likely first.
+//
+// Features the model has never seen are ignored, and confidences of all categories add up to one.
+// A model without training data proposes nothing.
+func (m *Model) Suggest(t transactions.Transaction) []Suggestion {
+	docs := m.Documents()
+	if docs == 0 {
+		return nil
+	}
+	if m.vocabulary == nil {
+		m.vocabulary = map[string]bool{}
+		for _, c := range m.Categories {
+			for f := range c.Features {
+				m.vocabulary[f] = true
+			}
+		}
+	}
+
+	var features []string
+	for _, f := range Features(t) {
+		if m.vocabulary[f] {
+			features = append(features, f)
+		}
+	}
+
+	ss := make([]Suggestion, 0, len(m.Categories))
+	best := math.Inf(-1)
+	for name, c := range m.Categories {
+		// Log probabilities with Laplace smoothing, converted to confidences below.
+		p := math.Log(float64(c.Documents) / float64(docs))
+		for _, f := range features {
+			p += math.Log(float64(c.Features[f]+1) / float64(c.Total+len(m.vocabulary)))
+		}
+		ss = append(ss, Suggestion{Category: name, Confidence: p})
+		best = max(best, p)
+	}
+
+	sum := 0.0
+	for i := range ss {
+		ss[i].Confidence = math.Exp(ss[i].Confidence - best)
+		sum += ss[i].Confidence
+	}
+	for i := range ss {
+		ss[i].Confidence /= sum
+	}
+	slices.SortFunc(ss, func(a, b Suggestion) int {
+		return cmp.Or(cmp.Compare(b.Confidence, a.Confidence), cmp.Compare(a.Category, b.Category))
+	})
+	return ss
+}
+
+// The features a model describes a transaction by: its payee, each distinct word of at least
+// three letters in its payee and description, and the direction and order of magnitude of its
+// amount.
+func Features(t transactions.Transaction) []string {
+	payee := t.Payee
+	if payee == "" && t.AccountHolder != "" {
+		payee = CleanPayee(t.AccountHolder)
+	}
+
+	var fs []string
+	if payee != "" {
+		fs = append(fs, "payee:"+strings.ToLower(payee))
+	}
+
+	seen := map[string]bool{}
+	isSeparator := func(r rune) bool { return !unicode.IsLetter(r) }
+	for _, w := range strings.FieldsFunc(strings.ToLower(payee+" "+t.Description), isSeparator) {
+		if len([]rune(w)) >= 3 && !seen[w] {
+			seen[w] = true
+			fs = append(fs, "word:"+w)
+		}
+	}
+
+	direction, size := "out", -t.Value
+	if t.Value >= 0 {
+		direction, size = "in", t.Value
+	}
+	magnitude := 0
+	for v := size / 100; v >= 10 && magnitude < 4; v /= 10 {
+		magnitude++
+	}
+	fs = append(fs, "amount:"+direction+strconv.Itoa(magnitude))
+	return fs
+}
+
+// Assigns the categories a model is confident about to transactions without one.
+type ModelClassifier struct {
+	model     *Model
+	threshold float64
+}
+
+// Creates a classifier from the model and threshold of the categorizer configuration.
+func NewModelClassifier(c config.CategorizerConfig) (*ModelClassifier, error) {
+	m, err := LoadModel(c.Model)
+	if err != nil {
+		return nil, err
+	}
+	return &ModelClassifier{model: m, threshold: Threshold(c)}, nil
+}
+
+// The configured threshold, or the default threshold if none is configured.
+func Threshold(c config.CategorizerConfig) float64 {
+	if c.Threshold > 0 {
+		return c.Threshold
+	}
+	return DefaultThreshold
+}
+
+// Assigns the most likely category to a transaction if its confidence reaches the threshold.
+//
+// Transactions that already have a category, or that do not move money, are left unchanged.
+func (c *ModelClassifier) Classify(t transactions.Transaction) transactions.Transaction {
+	if t.Category != "" || !t.IsMovement() {
+		return t
+	}
+	if ss := c.model.Suggest(t); len(ss) > 0 && ss[0].Confidence >= c.threshold {
+		t.Category = ss[0].Category
+	}
+	return t
+}
+
+// Classifies a stream of transactions.
+func (c *ModelClassifier) Apply(seq transactions.Seq) transactions.Seq {
+	return func(yield func(transactions.Transaction, error) bool) {
+		for t, err := range seq {
+			if err != nil {
+				yield(t, err)
+				return
+			}
+			if !yield(c.Classify(t), nil) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package classify_test
+
+import (
+	"path/filepath"
+	"slices"
+	"statements/pkg/classify"
+	"statements/pkg/config"
+	"statements/pkg/transactions"
+	"testing"
+)
+
+// Trains a model on a few transactions of groceries, rent and salary.
+func trainModel() *classify.Model {
+	m := classify.NewModel()
+	for _, t := range []transactions.Transaction{
+		{AccountHolder: "MAXIMA LV", Description: "PIRKUMS 4111 MAXIMA X45 RIGA", Value: -5161, Category: "Groceries"},
+		{AccountHolder: "MAXIMA LV", Description: "PIRKUMS 4111 MAXIMA X12 RIGA", Value: -1225, Category: "Groceries"},
+		{AccountHolder: "RIMI LATVIA", Description: "PIRKUMS 4111 RIMI HYPER RIGA", Value: -3410, Category: "Groceries"},
+		{AccountHolder: "LANDLORD", Description: "Rent January", Value: -70000, Category: "Housing"},
+		{AccountHolder: "LANDLORD", Description: "Rent February", Value: -70000, Category: "Housing"},
+		{AccountHolder: "EMPLOYER SIA", Description: "Alga", Value: 250000, Category: "Salary"},
+		{AccountHolder: "Unlabeled", Value: -100},
+	} {
+		m.Train(t)
+	}
+	return m
+}
+
+func TestFeatures(t *testing.T) {
+	got := classify.Features(transactions.Transaction{
+		AccountHolder: "MAXIMA LV",
+		Description:   "PIRKUMS 4111 Maxima X45 RIGA",
+		Value:         -5161,
+	})
+	want := []string{"payee:maxima", "word:maxima", "word:pirkums", "word:riga", "amount:out1"}
+	if !slices.Equal(got, want) {
+		t.Errorf("Features() = %v, want %v", got, want)
+	}
+
+	got = classify.Features(transactions.Transaction{Payee: "Employer", Value: 250000})
+	want = []string{"payee:employer", "word:employer", "amount:in3"}
+	if !slices.Equal(got, want) {
+		t.Errorf("Features() = %v, want %v", got, want)
+	}
+}
+
+func TestModel_Suggest(t *testing.T) {
+	m := trainModel()
+	if m.Documents() != 6 {
+		t.Errorf("Documents() = %d, want 6 labeled transactions", m.Documents())
+	}
+
+	tests := []struct {
+		name string
+		t    transactions.Transaction
+		want string
+	}{
+		{"known payee", transactions.Transaction{AccountHolder: "MAXIMA LV", Description: "PIRKUMS 4111 MAXIMA X99 RIGA", Value: -2000}, "Groceries"},
+		{"known words", transactions.Transaction{AccountHolder: "New Landlord", Description: "Rent March", Value: -72000}, "Housing"},
+		{"unknown payee", transactions.Transaction{AccountHolder: "Someone", Description: "Alga", Value: 240000}, "Salary"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ss := m.Suggest(tt.t)
+			if len(ss) != 3 {
+				t.Fatalf("Suggest() = %v, want a suggestion per category", ss)
+			}
+			if ss[0].Category != tt.want {
+				t.Errorf("Suggest()[0] = %+v, want %s", ss[0], tt.want)
+			}
+			sum := 0.0
+			for i, s := range ss {
+				sum += s.Confidence
+				if i > 0 && s.Confidence > ss[i-1].Confidence {
+					t.Errorf("Suggest() = %v, want the most likely first", ss)
+				}
+			}
+			if sum < 0.999 || sum > 1.001 {
+				t.Errorf("Suggest() confidences add up to %f, want 1", sum)
+			}
+		})
+	}
+
+	// Training after a suggestion extends the vocabulary.
+	leisure := transactions.Transaction{AccountHolder: "Forum Cinemas", Value: -1200, Category: "Leisure"}
+	m.Train(leisure)
+	if ss := m.Suggest(leisure); ss[0].Category != "Leisure" {
+		t.Errorf("Suggest() after training = %+v, want Leisure", ss[0])
+	}
+
+	if ss := classify.NewModel().Suggest(tests[0].t); ss != nil {
+		t.Errorf("Suggest() of an untrained model = %v, want none", ss)
+	}
+}
+
+func TestModelClassifier(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "model.json")
+	if err := trainModel().Save(path); err != nil {
+		t.Fatalf("Save() unexpected error: %v", err)
+	}
+
+	certain := transactions.Transaction{AccountHolder: "MAXIMA LV", Description: "PIRKUMS 4111 MAXIMA X45 RIGA", Value: -5161}
+	unsure := transactions.Transaction{AccountHolder: "Electro Shop", Description: "TV", Value: -89900}
+
+	c, err := classify.NewModelClassifier(config.CategorizerConfig{Model: path})
+	if err != nil {
+		t.Fatalf("NewModelClassifier() unexpected error: %v", err)
+	}
+	if got := c.Classify(certain).Category; got != "Groceries" {
+		t.Errorf("Classify().Category = %q, want Groceries", got)
+	}
+	if got := c.Classify(unsure).Category; got != "" {
+		t.Errorf("Classify().Category = %q, want none below the threshold", got)
+	}
+	certain.Category = "Gifts"
+	if got := c.Classify(certain).Category; got != "Gifts" {
+		t.Errorf("Classify().Category = %q, want the existing category kept", got)
+	}
+
+	c, err = classify.NewModelClassifier(config.CategorizerConfig{Model: path, Threshold: 0.01})
+	if err != nil {
+		t.Fatalf("NewModelClassifier() unexpected error: %v", err)
+	}
+	if got := c.Classify(unsure).Category; got == "" {
+		t.Error("Classify().Category is empty, want the best suggestion with a low threshold")
+	}
+
+	if _, err := classify.NewModelClassifier(config.CategorizerConfig{Model: filepath.Join(t.TempDir(), "missing.json")}); err == nil {
+		t.Error("NewModelClassifier() expected an error for a missing model")
+	}
+}
@@ -0,0 +1,186 @@
+package classify
+
+import (
+	"fmt"
+	"regexp"
+	"statements/pkg/config"
+	"statements/pkg/transactions"
+	"strings"
+	"unicode"
+)
+
+// A payee rule with its pattern compiled.
+type payeeRule struct {
+	payee    string
+	match    *regexp.Regexp
+	contains string
+}
+
+// Whether the rule matches a counterparty name or description.
+func (r payeeRule) matches(s string) bool {
+	if s == "" || r.match != nil && !r.match.MatchString(s) {
+		return false
+	}
+	return strings.Contains(strings.ToLower(s), r.contains)
+}
+
+// Assigns canonical payees to normalized transactions.
+//
+// The configured rules are tried first, and transactions matching none of them get the
+// counterparty name with card terminal details removed.
+type PayeeNormalizer struct {
+	rules []payeeRule
+}
+
+// Creates a payee normalizer from the payee rules in the configuration.
+func NewPayeeNormalizer(prs []config.PayeeRule) (*PayeeNormalizer, error) {
+	n := &PayeeNormalizer{}
+	for i, pr := range prs {
+		r := payeeRule{payee: pr.Payee, contains: strings.ToLower(pr.Contains)}
+		if pr.Match != "" {
+			re, err := regexp.Compile("(?i)" + pr.Match)
+			if err != nil {
+				return nil, fmt.Errorf("payee rule %d: invalid pattern: %v", i+1, err)
+			}
+			r.match = re
+		}
+		n.rules = append(n.rules, r)
+	}
+	return n, nil
+}
+
+// Assigns the payee of the first matching rule to a transaction, or its cleaned up counterparty
+// name, falling back to the description.
+//
+// Transactions that already have a payee, or that do not move money, are left unchanged.
+func (n *PayeeNormalizer) Normalize(t transactions.Transaction) transactions.Transaction {
+	if t.Payee != "" || !t.IsMovement() {
+		return t
+	}
+	for _, r := range n.rules {
+		if r.matches(t.AccountHolder) || r.matches(t.Description) {
+			t.Payee = r.payee
+			return t
+		}
+	}
+	if t.AccountHolder != "" {
+		t.Payee = CleanPayee(t.AccountHolder)
+	} else {
+		t.Payee = CleanPayee(t.Description)
+	}
+	return t
+}
+
+// Normalizes the payees of a stream of transactions.
+func (n *PayeeNormalizer) Apply(seq transactions.Seq) transactions.Seq {
+	return func(yield func(transactions.Transaction, error) bool) {
+		for t, err := range seq {
+			if err != nil {
+				yield(t, err)
+				return
+			}
+			if !yield(n.Normalize(t), nil) {
+				return
+			}
+		}
+	}
+}
+
+// Words card payment descriptions start with before the merchant name.
+var cardPrefixes = map[string]bool{
+	"PIRKUMS":  true,
+	"PIRKINYS": true,
+	"OST":      true,
+	"PURCHASE": true,
+	"POS":      true,
+}
+
+// Country codes card terminals append to the merchant name.
+var countryCodes = map[string]bool{
+	"LV": true, "LT": true, "EE": true, "FI": true, "SE": true, "NO": true, "DK": true, "DE": true,
+	"PL": true, "NL": true, "BE": true, "LU": true, "FR": true, "ES": true, "IT": true, "PT": true,
+	"AT": true, "CH": true, "CZ": true, "IE": true, "GB": true, "UK": true, "US": true,
+}
+
+// Cities card terminals print after the country code.
+var terminalCities = map[string]bool{
+	"RIGA": true, "RĪGA": true, "JURMALA": true, "JŪRMALA": true, "LIEPAJA": true, "LIEPĀJA": true,
+	"DAUGAVPILS": true, "JELGAVA": true, "VALMIERA": true, "VENTSPILS": true, "VILNIUS": true,
+	"KAUNAS": true, "KLAIPEDA": true, "TALLINN": true, "TARTU": true, "HELSINKI": true,
+	"STOCKHOLM": true,
+}
+
+// Whether the word at an index is a country code ending a merchant name, which is the case when
+// it is the last word or followed by a city or terminal code. Otherwise it is part of the name,
+// like in "SIA IT SERVICES".
+func isCountrySuffix(words []string, i int) bool {
+	if !countryCodes[words[i]] {
+		return false
+	}
+	if i == len(words)-1 {
+		return true
+	}
+	return terminalCities[strings.ToUpper(words[i+1])] || isTerminalCode(words[i+1])
+}
+
+// Cleans up a merchant name as printed by card terminals.
+//
+// Leading card payment markers and card numbers are dropped, and the name is cut at the first
+// store or terminal number or the country code before them, so that
+// "PIRKUMS 4111 MAXIMA LV 123 RIGA" and "Maxima X45" both become "Maxima". Names written in
+// capitals are converted to title case, keeping legal forms such as `SIA` in capitals.
+func CleanPayee(name string) string {
+	words := strings.Fields(name)
+	if len(words) == 0 {
+		return ""
+	}
+	start := 0
+	for start < len(words)-1 && (cardPrefixes[strings.ToUpper(words[start])] || isTerminalCode(words[start])) {
+		start++
+	}
+	end := start + 1
+	for end < len(words) && !isTerminalCode(words[end]) && !isCountrySuffix(words, end) {
+		end++
+	}
+
+	cleaned := strings.Trim(strings.Join(words[start:end], " "), " -*/,.:")
+	if cleaned == "" {
+		return strings.Join(words, " ")
+	}
+	if strings.ToUpper(cleaned) == cleaned {
+		cleaned = titleCase(cleaned)
+	}
+	return cleaned
+}
+
+// Whether a word is a number or code rather than part of a name, such as a card number, date,
+// or a store number like `X45`.
+func isTerminalCode(word string) bool {
+	letters, digits := 0, 0
+	for _, r := range word {
+		switch {
+		case unicode.IsLetter(r):
+			letters++
+		case unicode.IsDigit(r):
+			digits++
+		}
+	}
+	return digits > 0 && letters <= 2
+}
+
+// Legal forms of companies kept in capitals when converting names to title case.
+var legalForms = map[string]bool{
+	"SIA": true, "AS": true, "UAB": true, "AB": true, "OU": true, "OÜ": true, "OY": true,
+	"SA": true, "AG": true, "LLC": true, "LTD": true, "PLC": true, "INC": true,
+}
+
+// Converts the words of a name in capitals to title case, keeping legal forms in capitals.
+func titleCase(name string) string {
+	words := strings.Fields(name)
+	for i, w := range words {
+		if rs := []rune(w); !legalForms[w] {
+			words[i] = string(rs[0]) + strings.ToLower(string(rs[1:]))
+		}
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,101 @@
+package classify_test
+
+import (
+	"statements/pkg/classify"
+	"statements/pkg/config"
+	"statements/pkg/transactions"
+	"testing"
+)
+
+func TestCleanPayee(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"MAXIMA LV 123 RIGA", "Maxima"},
+		{"Maxima X45", "Maxima"},
+		{"MAXIMA LV RIGA", "Maxima"},
+		{"CIRCLE K LV", "Circle K"},
+		{"SIA IT SERVICES", "SIA It Services"},
+		{"PIRKUMS 4111***1234 12.03.2025 MAXIMA X45 RIGA", "Maxima"},
+		{"EMPLOYER SIA", "Employer SIA"},
+		{"Spotify AB", "Spotify AB"},
+		{"Tele2", "Tele2"},
+		{"GYM CLUB", "Gym Club"},
+		{"  ", ""},
+		{"4111", "4111"},
+	}
+
+	for _, tt := range tests {
+		if got := classify.CleanPayee(tt.name); got != tt.want {
+			t.Errorf("CleanPayee(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestPayeeNormalizer_Normalize(t *testing.T) {
+	n, err := classify.NewPayeeNormalizer([]config.PayeeRule{
+		{Payee: "Netflix", Contains: "NETFLIX"},
+		{Payee: "Rimi", Match: `^rimi\b`},
+		{Payee: "Bolt Food", Match: `^bolt`, Contains: "food"},
+	})
+	if err != nil {
+		t.Fatalf("NewPayeeNormalizer() unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		t    transactions.Transaction
+		want string
+	}{
+		{
+			name: "contains rule ignoring case",
+			t:    transactions.Transaction{AccountHolder: "Netflix.com"},
+			want: "Netflix",
+		},
+		{
+			name: "pattern rule on the description",
+			t:    transactions.Transaction{Description: "RIMI HYPER 12"},
+			want: "Rimi",
+		},
+		{
+			name: "pattern and substring must both match",
+			t:    transactions.Transaction{AccountHolder: "BOLT OPERATIONS"},
+			want: "Bolt Operations",
+		},
+		{
+			name: "cleaned counterparty without a matching rule",
+			t:    transactions.Transaction{AccountHolder: "MAXIMA LV", Description: "PIRKUMS 4111 MAXIMA X45 RIGA"},
+			want: "Maxima",
+		},
+		{
+			name: "description without a counterparty",
+			t:    transactions.Transaction{Description: "PIRKUMS 4111 CIRCLE K LV 7"},
+			want: "Circle K",
+		},
+		{
+			name: "existing payee is kept",
+			t:    transactions.Transaction{AccountHolder: "Netflix.com", Payee: "Streaming"},
+			want: "Streaming",
+		},
+		{
+			name: "balances are not normalized",
+			t:    transactions.Transaction{Kind: transactions.KindClosingBalance, AccountHolder: "MAXIMA LV"},
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := n.Normalize(tt.t).Payee; got != tt.want {
+				t.Errorf("Normalize().Payee = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewPayeeNormalizer_InvalidPattern(t *testing.T) {
+	if _, err := classify.NewPayeeNormalizer([]config.PayeeRule{{Payee: "X", Match: "("}}); err == nil {
+		t.Error("NewPayeeNormalizer() expected error but got none")
+	}
+}
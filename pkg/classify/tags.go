@@ -0,0 +1,61 @@
+package classify
+
+import (
+	"fmt"
+	"statements/pkg/config"
+	"statements/pkg/transactions"
+)
+
+// A tag rule with its filters decoded.
+type tagRule struct {
+	tags    []string
+	filters []config.Filter
+}
+
+// Adds tags to normalized transactions based on the configured rules.
+type Tagger struct {
+	rules []tagRule
+}
+
+// Creates a tagger from the tag rules in the configuration.
+func NewTagger(trs []config.TagRule) (*Tagger, error) {
+	tg := &Tagger{}
+	for i, tr := range trs {
+		fs, err := transactions.DecodeFilters(tr.Filters)
+		if err != nil {
+			return nil, fmt.Errorf("tag rule %d: %v", i+1, err)
+		}
+		tg.rules = append(tg.rules, tagRule{tags: tr.Tags, filters: fs})
+	}
+	return tg, nil
+}
+
+// Adds the tags of every matching rule to a transaction, keeping the tags it already has.
+//
+// Transactions that do not move money are left unchanged.
+func (tg *Tagger) Tag(t transactions.Transaction) transactions.Transaction {
+	if !t.IsMovement() {
+		return t
+	}
+	for _, r := range tg.rules {
+		if t.Matches(r.filters) {
+			t.Tags = transactions.AddTags(t.Tags, r.tags...)
+		}
+	}
+	return t
+}
+
+// Tags a stream of transactions.
+func (tg *Tagger) Apply(seq transactions.Seq) transactions.Seq {
+	return func(yield func(transactions.Transaction, error) bool) {
+		for t, err := range seq {
+			if err != nil {
+				yield(t, err)
+				return
+			}
+			if !yield(tg.Tag(t), nil) {
+				return
+			}
+		}
+	}
+}
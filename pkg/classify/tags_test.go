@@ -0,0 +1,69 @@
+package classify_test
+
+import (
+	"encoding/json"
+	"slices"
+	"statements/pkg/classify"
+	"statements/pkg/config"
+	"statements/pkg/transactions"
+	"testing"
+	"time"
+)
+
+func TestTagger_Tag(t *testing.T) {
+	var trs []config.TagRule
+	if err := json.Unmarshal([]byte(`[
+		{"tags": ["#vacation-2025"], "filters": [
+			{"field": "date", "condition": "GREATER_THAN_EQUAL", "comparison": "01.07.2025"},
+			{"field": "date", "condition": "LESS_THAN_EQUAL", "comparison": "14.07.2025"}
+		]},
+		{"tags": ["reimbursable"], "filters": [{"field": "category", "condition": "EQUAL", "comparison": "Travel"}]},
+		{"tags": ["tax-deductible", "reimbursable"], "filters": [{"field": "tags", "condition": "EQUAL", "comparison": "reimbursable"}]}
+	]`), &trs); err != nil {
+		t.Fatalf("Failed to parse tag rules: %v", err)
+	}
+
+	tg, err := classify.NewTagger(trs)
+	if err != nil {
+		t.Fatalf("NewTagger() unexpected error: %v", err)
+	}
+
+	summer := transactions.Transaction{Date: time.Date(2025, 7, 3, 0, 0, 0, 0, time.UTC), Value: -1000}
+	tests := []struct {
+		name string
+		t    transactions.Transaction
+		want []string
+	}{
+		{"date range", summer, []string{"vacation-2025"}},
+		{
+			"later rules see earlier tags",
+			transactions.Transaction{Date: time.Date(2025, 7, 3, 0, 0, 0, 0, time.UTC), Category: "Travel", Value: -1000},
+			[]string{"vacation-2025", "reimbursable", "tax-deductible"},
+		},
+		{
+			"existing tags are kept",
+			transactions.Transaction{Date: time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC), Tags: []string{"gift"}, Value: -1000},
+			[]string{"gift"},
+		},
+		{
+			"balances are left unchanged",
+			transactions.Transaction{Kind: transactions.KindClosingBalance, Date: time.Date(2025, 7, 3, 0, 0, 0, 0, time.UTC)},
+			nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tg.Tag(tt.t).Tags; !slices.Equal(got, tt.want) {
+				t.Errorf("Tag().Tags = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewTagger_InvalidFilter(t *testing.T) {
+	trs := []config.TagRule{{Tags: []string{"x"}, Filters: []config.RawFilter{{Raw: []byte(`{"field": "unknown"}`)}}}}
+	if _, err := classify.NewTagger(trs); err == nil {
+		t.Error("NewTagger() expected error for an unknown field")
+	}
+}
@@ -0,0 +1,186 @@
+package classify
+
+import (
+	"slices"
+	"statements/pkg/config"
+	"statements/pkg/transactions"
+	"strings"
+	"time"
+)
+
+// The number of days the two sides of a transfer can be booked apart if no window is configured.
+const DefaultTransferWindow = 3
+
+// Pairs the debits and credits of transfers between own accounts.
+type TransferMatcher struct {
+	accounts map[string]bool
+	window   time.Duration
+}
+
+// Creates a transfer matcher from the configured own accounts.
+func NewTransferMatcher(c config.TransferConfig) *TransferMatcher {
+	m := &TransferMatcher{accounts: map[string]bool{}, window: time.Duration(DefaultTransferWindow) * 24 * time.Hour}
+	if c.Window > 0 {
+		m.window = time.Duration(c.Window) * 24 * time.Hour
+	}
+	for _, a := range c.Accounts {
+		if a := normalizeAccount(a); a != "" {
+			m.accounts[a] = true
+		}
+	}
+	return m
+}
+
+// Formats an account number for comparison, ignoring case and spaces.
+func normalizeAccount(a string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(a), ""))
+}
+
+// Marks the transfers between own accounts among the transactions, pairing each debit with the
+// credit of the same amount and currency booked closest to it within the window.
+//
+// Transactions that are already paired keep their transfer, so matching can be repeated when
+// more accounts are added, and mark their other side if it was stored without the pairing.
+func (m *TransferMatcher) Match(ts []transactions.Transaction) {
+	if len(m.accounts) == 0 {
+		return
+	}
+
+	ids := map[string]int{}
+	for i, t := range ts {
+		ids[t.ID] = i
+	}
+	for _, t := range ts {
+		if j, ok := ids[t.Transfer]; ok && t.IsTransfer() && !ts[j].IsTransfer() {
+			ts[j].Transfer = t.ID
+		}
+	}
+
+	type amount struct {
+		currency string
+		value    int
+	}
+	var debits []int
+	credits := map[amount][]int{}
+	for i, t := range ts {
+		if !pairable(t) {
+			continue
+		}
+		if t.Value < 0 {
+			debits = append(debits, i)
+			continue
+		}
+		k := amount{t.Currency, t.Value}
+		credits[k] = append(credits[k], i)
+	}
+	slices.SortStableFunc(debits, func(a, b int) int { return ts[a].Date.Compare(ts[b].Date) })
+
+	for _, d := range debits {
+		m.pairClosest(ts, d, credits[amount{ts[d].Currency, -ts[d].Value}])
+	}
+}
+
+// Whether a transaction can be paired: it moves money, has an ID and is not paired yet.
+func pairable(t transactions.Transaction) bool {
+	return t.IsMovement() && !t.IsTransfer() && t.ID != "" && t.Value != 0
+}
+
+// Pairs the debit at an index with the unpaired credit of the same amount and currency among the
+// candidates that is booked closest to it within the window, preferring earlier candidates.
+func (m *TransferMatcher) pairClosest(ts []transactions.Transaction, d int, candidates []int) {
+	best, bestGap := -1, time.Duration(0)
+	for _, c := range candidates {
+		if ts[c].Value != -ts[d].Value || ts[c].Currency != ts[d].Currency || !pairable(ts[c]) || !m.pairs(ts[d], ts[c]) {
+			continue
+		}
+		gap := ts[c].Date.Sub(ts[d].Date).Abs()
+		if gap <= m.window && (best < 0 || gap < bestGap) {
+			best, bestGap = c, gap
+		}
+	}
+	if best >= 0 {
+		ts[d].Transfer, ts[best].Transfer = ts[best].ID, ts[d].ID
+	}
+}
+
+// Whether a debit and a credit can be the two sides of a transfer: they are on different
+// accounts, at least one names an own account as its counterparty, and neither names an account
+// other than the one of the other side.
+func (m *TransferMatcher) pairs(debit, credit transactions.Transaction) bool {
+	da, ca := normalizeAccount(debit.Account), normalizeAccount(credit.Account)
+	dc, cc := normalizeAccount(debit.CounterpartyAccount), normalizeAccount(credit.CounterpartyAccount)
+	switch {
+	case da != "" && da == ca:
+		return false
+	case !m.accounts[dc] && !m.accounts[cc]:
+		return false
+	case dc != "" && ca != "" && dc != ca:
+		return false
+	case cc != "" && da != "" && cc != da:
+		return false
+	}
+	return true
+}
+
+// Marks the transfers in a stream of transactions in date order, such as the merged inputs or
+// the ledger, with the same pairs as `Match` as long as stored pairs are booked within the window.
+//
+// Only the transactions within three windows of the latest date are buffered: a debit is paired
+// once all credits within two windows of it are known, so that the stored pairs of its
+// candidates are completed first, and a transaction is passed on once no debit that is still to
+// be paired can reach it. The stream is returned unchanged if no own account is configured.
+func (m *TransferMatcher) Apply(seq transactions.Seq) transactions.Seq {
+	if len(m.accounts) == 0 {
+		return seq
+	}
+	return func(yield func(transactions.Transaction, error) bool) {
+		var buf []transactions.Transaction
+		// The number of leading buffered transactions whose debits are paired.
+		paired := 0
+		// Pairs the debits and passes on the transactions that are settled before a date.
+		flush := func(date time.Time, end bool) bool {
+			candidates := indexes(buf)
+			for ; paired < len(buf) && (end || date.Sub(buf[paired].Date) > 2*m.window); paired++ {
+				if pairable(buf[paired]) && buf[paired].Value < 0 {
+					m.pairClosest(buf, paired, candidates)
+				}
+			}
+			for paired > 0 && (end || date.Sub(buf[0].Date) > 3*m.window) {
+				if !yield(buf[0], nil) {
+					return false
+				}
+				buf, paired = buf[1:], paired-1
+			}
+			return true
+		}
+
+		for t, err := range seq {
+			if err != nil {
+				yield(t, err)
+				return
+			}
+			if !flush(t.Date, false) {
+				return
+			}
+			for i := range buf {
+				switch {
+				case t.IsTransfer() && buf[i].ID == t.Transfer && !buf[i].IsTransfer():
+					buf[i].Transfer = t.ID
+				case buf[i].IsTransfer() && buf[i].Transfer == t.ID && !t.IsTransfer():
+					t.Transfer = buf[i].ID
+				}
+			}
+			buf = append(buf, t)
+		}
+		flush(time.Time{}, true)
+	}
+}
+
+// The indexes of all transactions in a buffer.
+func indexes(buf []transactions.Transaction) []int {
+	is := make([]int, len(buf))
+	for i := range is {
+		is[i] = i
+	}
+	return is
+}
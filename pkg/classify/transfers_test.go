@@ -0,0 +1,157 @@
+package classify_test
+
+import (
+	"fmt"
+	"slices"
+	"statements/pkg/classify"
+	"statements/pkg/config"
+	"statements/pkg/transactions"
+	"testing"
+	"time"
+)
+
+func transferTransactions() []transactions.Transaction {
+	day := func(d int) time.Time { return time.Date(2025, 3, d, 0, 0, 0, 0, time.UTC) }
+	return []transactions.Transaction{
+		{ID: "C1", Account: "LV01HABA0000000000001", Date: day(1), CounterpartyAccount: "LV02 HABA 0000 0000 0000 2", Value: -50000, Currency: "EUR"},
+		{ID: "C2", Account: "LV01HABA0000000000001", Date: day(1), AccountHolder: "Maxima", Value: -2310, Currency: "EUR"},
+		{ID: "S1", Account: "LV02HABA0000000000002", Date: day(2), Value: 50000, Currency: "EUR"},
+		{ID: "S2", Account: "LV02HABA0000000000002", Date: day(3), Value: 2310, Currency: "EUR"},
+		{ID: "C3", Account: "LV01HABA0000000000001", Date: day(5), CounterpartyAccount: "LV02HABA0000000000002", Value: -10000, Currency: "EUR"},
+		{ID: "S3", Account: "LV02HABA0000000000002", Date: day(20), CounterpartyAccount: "LV01HABA0000000000001", Value: 10000, Currency: "EUR"},
+		{ID: "C4", Account: "LV01HABA0000000000001", Date: day(6), CounterpartyAccount: "LV99OTHR0000000000009", Value: -7000, Currency: "EUR"},
+		{ID: "S4", Account: "LV02HABA0000000000002", Date: day(6), CounterpartyAccount: "LV01HABA0000000000001", Value: 7000, Currency: "EUR"},
+		{ID: "C5", Account: "LV01HABA0000000000001", Date: day(7), Kind: transactions.KindClosingBalance, Value: -20000, Currency: "EUR"},
+		{ID: "S5", Account: "LV02HABA0000000000002", Date: day(7), CounterpartyAccount: "LV01HABA0000000000001", Value: 20000, Currency: "EUR"},
+	}
+}
+
+func TestTransferMatcher_Match(t *testing.T) {
+	m := classify.NewTransferMatcher(config.TransferConfig{Accounts: []string{"LV01HABA0000000000001", "lv02haba0000000000002"}})
+	ts := transferTransactions()
+	m.Match(ts)
+
+	want := map[string]string{
+		"C1": "S1", "S1": "C1",
+		// Payments without an own counterparty are not transfers, even with a matching amount.
+		"C2": "", "S2": "",
+		// Credits outside the window are not paired.
+		"C3": "", "S3": "",
+		// Debits naming another counterparty are not paired, even if the credit names the account.
+		"C4": "", "S4": "",
+		// Statement rows are not paired.
+		"C5": "", "S5": "",
+	}
+	for _, tr := range ts {
+		if tr.Transfer != want[tr.ID] {
+			t.Errorf("Match() transfer of %s = %q, want %q", tr.ID, tr.Transfer, want[tr.ID])
+		}
+	}
+}
+
+func TestTransferMatcher_Window(t *testing.T) {
+	m := classify.NewTransferMatcher(config.TransferConfig{Accounts: []string{"LV02HABA0000000000002"}, Window: 30})
+	ts := transferTransactions()
+	m.Match(ts)
+	if ts[4].Transfer != "S3" || ts[5].Transfer != "C3" {
+		t.Errorf("Match() with a 30 day window paired C3 with %q and S3 with %q, want each other", ts[4].Transfer, ts[5].Transfer)
+	}
+}
+
+func TestTransferMatcher_ClosestCredit(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 3, d, 0, 0, 0, 0, time.UTC) }
+	ts := []transactions.Transaction{
+		{ID: "D1", Account: "A", Date: day(10), CounterpartyAccount: "B", Value: -100, Currency: "EUR"},
+		{ID: "K1", Account: "B", Date: day(8), Value: 100, Currency: "EUR"},
+		{ID: "K2", Account: "B", Date: day(11), Value: 100, Currency: "EUR"},
+		{ID: "K3", Account: "B", Date: day(11), Value: 100, Currency: "USD"},
+	}
+	classify.NewTransferMatcher(config.TransferConfig{Accounts: []string{"A", "B"}}).Match(ts)
+	if ts[0].Transfer != "K2" || ts[1].IsTransfer() || ts[2].Transfer != "D1" || ts[3].IsTransfer() {
+		t.Errorf("Match() = %+v, want D1 paired with the closest credit K2", ts)
+	}
+}
+
+func TestTransferMatcher_StoredPair(t *testing.T) {
+	ts := transferTransactions()
+	ts[2].Transfer = "C1"
+	classify.NewTransferMatcher(config.TransferConfig{Accounts: []string{"LV02HABA0000000000002"}}).Match(ts)
+	if ts[0].Transfer != "S1" || ts[2].Transfer != "C1" {
+		t.Errorf("Match() paired C1 with %q and S1 with %q, want the stored pair completed", ts[0].Transfer, ts[2].Transfer)
+	}
+}
+
+func TestTransferMatcher_Apply(t *testing.T) {
+	got, err := transactions.Collect(classify.NewTransferMatcher(config.TransferConfig{}).Apply(transactions.All(transferTransactions())))
+	if err != nil {
+		t.Fatalf("Apply() unexpected error: %v", err)
+	}
+	for _, tr := range got {
+		if tr.IsTransfer() {
+			t.Errorf("Apply() without own accounts marked %s as a transfer", tr.ID)
+		}
+	}
+
+	m := classify.NewTransferMatcher(config.TransferConfig{Accounts: []string{"LV02HABA0000000000002"}})
+	got, err = transactions.Collect(m.Apply(transactions.All(transferTransactions())))
+	if err != nil {
+		t.Fatalf("Apply() unexpected error: %v", err)
+	}
+	if len(got) != len(transferTransactions()) || got[0].Transfer != "S1" {
+		t.Errorf("Apply() = %+v, want all transactions with C1 paired", got)
+	}
+}
+
+func TestTransferMatcher_ApplyMatchesInDateOrder(t *testing.T) {
+	configs := []config.TransferConfig{
+		{Accounts: []string{"LV02HABA0000000000002"}},
+		{Accounts: []string{"LV02HABA0000000000002"}, Window: 30},
+	}
+	for _, c := range configs {
+		for _, stored := range []bool{false, true} {
+			ts := transferTransactions()
+			if stored {
+				ts[2].Transfer = "C1"
+			}
+			slices.SortStableFunc(ts, func(a, b transactions.Transaction) int { return a.Date.Compare(b.Date) })
+
+			got, err := transactions.Collect(classify.NewTransferMatcher(c).Apply(transactions.All(ts)))
+			if err != nil {
+				t.Fatalf("Apply() unexpected error: %v", err)
+			}
+			classify.NewTransferMatcher(c).Match(ts)
+			if !slices.EqualFunc(got, ts, func(a, b transactions.Transaction) bool { return a.ID == b.ID && a.Transfer == b.Transfer }) {
+				t.Errorf("Apply() with %+v and stored pair %t = %+v, want %+v", c, stored, got, ts)
+			}
+		}
+	}
+}
+
+func TestTransferMatcher_ApplyStreams(t *testing.T) {
+	read := 0
+	seq := func(yield func(transactions.Transaction, error) bool) {
+		for d := range 60 {
+			read++
+			tr := transactions.Transaction{ID: fmt.Sprint(d), Account: "A", Date: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, d), CounterpartyAccount: "B", Value: -100, Currency: "EUR"}
+			if d == 1 {
+				tr.Account, tr.CounterpartyAccount, tr.Value = "B", "", 100
+			}
+			if !yield(tr, nil) {
+				return
+			}
+		}
+	}
+
+	for tr, err := range classify.NewTransferMatcher(config.TransferConfig{Accounts: []string{"B"}}).Apply(seq) {
+		if err != nil {
+			t.Fatalf("Apply() unexpected error: %v", err)
+		}
+		if tr.ID != "0" || tr.Transfer != "1" {
+			t.Errorf("Apply() first transaction = %+v, want 0 paired with 1", tr)
+		}
+		if read > 3*classify.DefaultTransferWindow+2 {
+			t.Errorf("Apply() read %d transactions before the first one, want a buffer bounded by the window", read)
+		}
+		break
+	}
+}
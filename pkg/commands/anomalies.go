@@ -0,0 +1,87 @@
+package commands
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"statements/pkg/analysis"
+	"statements/pkg/config"
+	"statements/pkg/ctime"
+	"statements/pkg/transactions"
+
+	"github.com/spf13/cobra"
+)
+
+func NewAnomaliesCommand() *cobra.Command {
+	var history historyFlags
+	var threshold, newPayee *float64
+	var days *int
+	var since, format, confile *string
+
+	cmd := &cobra.Command{
+		Use:   "anomalies",
+		Short: "Find unusual charges in bank statements",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := config.Parse(*confile)
+			if err != nil {
+				return fmt.Errorf("could not parse config file: %v", err)
+			}
+
+			f, err := parseReportFormat(*format)
+			if err != nil {
+				return err
+			}
+
+			opts := analysis.AnomalyOptions{
+				Threshold:      *threshold,
+				DuplicateDays:  *days,
+				NewPayeeAmount: int(math.Round(*newPayee * 100)),
+			}
+			if *since != "" {
+				opts.Since, err = time.Parse(ctime.LittleEndianDateOnly, *since)
+				if err != nil {
+					return fmt.Errorf("invalid start date %q: %v", *since, err)
+				}
+			}
+
+			seq, err := history.load(cmd.ErrOrStderr(), c, false)
+			if err != nil {
+				return err
+			}
+			ts, err := transactions.Collect(seq)
+			if err != nil {
+				return err
+			}
+
+			return anomaliesReport(analysis.FindAnomalies(ts, opts)).write(cmd.OutOrStdout(), f)
+		},
+	}
+
+	history = addHistoryFlags(cmd)
+
+	threshold = cmd.Flags().Float64("threshold", 3.5, "deviations above the usual amount from which a charge is an outlier")
+
+	days = cmd.Flags().Int("duplicate-days", 3, "number of days within which equal charges are possible duplicates")
+
+	newPayee = cmd.Flags().Float64("new-payee-amount", 100, "amount from which a charge to a new counterparty is reported")
+
+	since = cmd.Flags().String("since", "", "only report anomalies on or after this date (DD.MM.YYYY), defaulting to 30 days into the history")
+
+	format = cmd.Flags().StringP("format", "f", string(reportTable), fmt.Sprintf("report format, one of %v", reportFormats))
+
+	confile = cmd.Flags().String("config", config.DefaultConfig, "configuration file to use")
+
+	return cmd
+}
+
+// Builds a report with a row per anomaly.
+func anomaliesReport(as []analysis.Anomaly) report {
+	r := report{columns: []string{"date", "counterparty", "amount", "currency", "kind", "reason"}}
+	for _, a := range as {
+		t := a.Transaction
+		r.add(t.Date.Format(time.DateOnly), analysis.Counterparty(t), amount(t.Value), t.Currency, string(a.Kind), a.Reason)
+	}
+	return r
+}
@@ -0,0 +1,98 @@
+package commands
+
+import (
+	"fmt"
+	"time"
+
+	"statements/pkg/analysis"
+	"statements/pkg/config"
+	"statements/pkg/ctime"
+	"statements/pkg/transactions"
+
+	"github.com/spf13/cobra"
+)
+
+func NewBudgetCommand() *cobra.Command {
+	var history historyFlags
+	var date, format, confile *string
+
+	cmd := &cobra.Command{
+		Use:   "budget",
+		Short: "Compare spending against the configured budgets",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := config.Parse(*confile)
+			if err != nil {
+				return fmt.Errorf("could not parse config file: %v", err)
+			}
+
+			f, err := parseReportFormat(*format)
+			if err != nil {
+				return err
+			}
+
+			budgets, err := analysis.NewBudgets(c.Budgets)
+			if err != nil {
+				return err
+			}
+			if len(budgets) == 0 {
+				return fmt.Errorf("no budgets configured")
+			}
+
+			seq, err := history.load(cmd.ErrOrStderr(), c, false)
+			if err != nil {
+				return err
+			}
+			ts, err := transactions.Collect(seq)
+			if err != nil {
+				return err
+			}
+			if err := analysis.ResolveCurrencies(budgets, ts); err != nil {
+				return err
+			}
+
+			var asOf time.Time
+			if *date != "" {
+				asOf, err = time.Parse(ctime.LittleEndianDateOnly, *date)
+				if err != nil {
+					return fmt.Errorf("invalid date %q: %v", *date, err)
+				}
+			} else {
+				for _, t := range ts {
+					if t.Date.After(asOf) {
+						asOf = t.Date
+					}
+				}
+			}
+
+			r := report{columns: []string{"budget", "period", "currency", "limit", "carryOver", "spent", "remaining", "projected", "status"}}
+			for _, b := range budgets {
+				s := b.Status(ts, asOf)
+				r.add(s.Budget, s.Period, s.Currency, amount(s.Limit), amount(s.CarryOver), amount(s.Spent), amount(s.Remaining), amount(s.Projected), budgetState(s))
+			}
+			return r.write(cmd.OutOrStdout(), f)
+		},
+	}
+
+	history = addHistoryFlags(cmd)
+
+	date = cmd.Flags().String("date", "", "date to report the budgets on (DD.MM.YYYY), defaulting to the latest transaction")
+
+	format = cmd.Flags().StringP("format", "f", string(reportTable), fmt.Sprintf("report format, one of %v", reportFormats))
+
+	confile = cmd.Flags().String("config", config.DefaultConfig, "configuration file to use")
+
+	return cmd
+}
+
+// Describes whether a budget is exceeded, or is going to be at the current pace.
+func budgetState(s analysis.BudgetStatus) string {
+	switch {
+	case s.Remaining < 0:
+		return "over"
+	case s.Projected > s.Limit+s.CarryOver:
+		return "at risk"
+	default:
+		return "ok"
+	}
+}
@@ -0,0 +1,212 @@
+package commands
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"statements/pkg/classify"
+	"statements/pkg/config"
+	"statements/pkg/transactions"
+
+	"github.com/spf13/cobra"
+)
+
+func NewCategorizeCommand() *cobra.Command {
+	var modfile, confile *string
+
+	cmd := &cobra.Command{
+		Use:   "categorize",
+		Short: "Categorize transactions with a model trained on labeled history",
+	}
+
+	// Parses the config and resolves the model file from the flag, the config or the default.
+	setup := func() (config.Config, string, error) {
+		c, err := config.Parse(*confile)
+		if err != nil {
+			return c, "", fmt.Errorf("could not parse config file: %v", err)
+		}
+		path := *modfile
+		if path == "" {
+			path = c.Categorizer.Model
+		}
+		if path == "" {
+			path = classify.DefaultModel
+		}
+		return c, path, nil
+	}
+
+	trainCmd := &cobra.Command{
+		Use:   "train file...",
+		Short: "Train a model on CSV files of transactions with a category column",
+		Long: "Train a model on CSV files of transactions with a category column, such as processed output " +
+			"with categories filled in by hand. Files are read with the delimiter and number format of the " +
+			"output configuration and need a header row naming the columns. Rows without a category are skipped.",
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, path, err := setup()
+			if err != nil {
+				return err
+			}
+
+			m := classify.NewModel()
+			for _, file := range args {
+				ts, err := readLabeled(file, c.Output.WithDefaults())
+				if err != nil {
+					return err
+				}
+				for _, t := range ts {
+					m.Train(t)
+				}
+			}
+			if len(m.Categories) < 2 {
+				return fmt.Errorf("training needs transactions of at least two categories, found %d", len(m.Categories))
+			}
+
+			if err := m.Save(path); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Trained on %d transaction(s) in %d categories, written to %s\n",
+				m.Documents(), len(m.Categories), path)
+			return nil
+		},
+	}
+
+	var history historyFlags
+	var threshold *float64
+	var all *bool
+	var format *string
+
+	suggestCmd := &cobra.Command{
+		Use:   "suggest",
+		Short: "Suggest categories for transactions without one",
+		Long: "Suggest categories for transactions without one, with the confidence of the model. " +
+			"Suggestions reaching the threshold are the ones applied automatically while processing " +
+			"if the model is configured.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, path, err := setup()
+			if err != nil {
+				return err
+			}
+			f, err := parseReportFormat(*format)
+			if err != nil {
+				return err
+			}
+			m, err := classify.LoadModel(path)
+			if err != nil {
+				return err
+			}
+			if !cmd.Flags().Changed("threshold") {
+				*threshold = classify.Threshold(c.Categorizer)
+			}
+
+			// Keeps a configured model from applying suggestions while loading, so all are reported.
+			c.Categorizer.Model = ""
+			seq, err := history.loadRows(cmd.ErrOrStderr(), c, false)
+			if err != nil {
+				return err
+			}
+
+			r := report{columns: []string{"date", "payee", "description", "value", "currency", "category", "suggestion", "confidence", "apply"}}
+			for t, err := range seq {
+				if err != nil {
+					return err
+				}
+				if !t.IsMovement() || t.Category != "" && !*all {
+					continue
+				}
+				ss := m.Suggest(t)
+				if len(ss) == 0 {
+					continue
+				}
+				apply := ""
+				if ss[0].Confidence >= *threshold {
+					apply = "yes"
+				}
+				r.add(t.Date.Format(time.DateOnly), t.Payee, t.Description, amount(t.Value), t.Currency, t.Category,
+					ss[0].Category, percent{ss[0].Confidence * 100, true}, apply)
+			}
+			return r.write(cmd.OutOrStdout(), f)
+		},
+	}
+
+	history = addHistoryFlags(suggestCmd)
+
+	threshold = suggestCmd.Flags().Float64("threshold", classify.DefaultThreshold, "confidence from which suggestions are applied, defaulting to the configured threshold")
+
+	all = suggestCmd.Flags().Bool("all", false, "also suggest for transactions that already have a category")
+
+	format = suggestCmd.Flags().StringP("format", "f", string(reportTable), fmt.Sprintf("report format, one of %v", reportFormats))
+
+	modfile = cmd.PersistentFlags().String("model", "", fmt.Sprintf("model file, defaulting to the configured model or %s", classify.DefaultModel))
+
+	confile = cmd.PersistentFlags().String("config", config.DefaultConfig, "configuration file to use")
+
+	cmd.AddCommand(trainCmd)
+	cmd.AddCommand(suggestCmd)
+
+	return cmd
+}
+
+// Reads transactions from a CSV file with a header row, such as written by the CSV output format.
+//
+// The category column is required, while the payee, counterparty, description, value and
+// currency columns are read if present.
+func readLabeled(path string, o config.OutputConfig) ([]transactions.Transaction, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not open labeled file: %v", err)
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.Comma = []rune(o.Delimiter)[0]
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+	rows, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%s: file is empty", path)
+	}
+
+	header := rows[0]
+	header[0] = strings.TrimPrefix(header[0], "\ufeff")
+	column := func(name string) int { return slices.Index(header, name) }
+	category := column("category")
+	if category < 0 {
+		return nil, fmt.Errorf("%s: no category column in header %v", path, header)
+	}
+	payee, holder, description, value, currency := column("payee"), column("accountHolder"), column("description"), column("value"), column("currency")
+	cell := func(row []string, i int) string {
+		if i < 0 || i >= len(row) {
+			return ""
+		}
+		return row[i]
+	}
+
+	ts := make([]transactions.Transaction, 0, len(rows)-1)
+	for i, row := range rows[1:] {
+		t := transactions.Transaction{
+			Kind:          transactions.KindTransaction,
+			Payee:         cell(row, payee),
+			AccountHolder: cell(row, holder),
+			Description:   cell(row, description),
+			Currency:      cell(row, currency),
+			Category:      cell(row, category),
+		}
+		if v := cell(row, value); v != "" {
+			t.Value, err = transactions.ParseValue(v, o.Decimal, o.Thousands)
+			if err != nil {
+				return nil, fmt.Errorf("%s: row %d: %v", path, i+2, err)
+			}
+		}
+		ts = append(ts, t)
+	}
+	return ts, nil
+}
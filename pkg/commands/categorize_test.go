@@ -0,0 +1,41 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"statements/pkg/config"
+)
+
+func TestReadLabeled(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "labeled.csv")
+	content := "\ufeffdate;accountHolder;description;value;category\n" +
+		"01.01.2025;MAXIMA LV;\"PIRKUMS; MAXIMA\";-1 051,61;Groceries\n" +
+		"05.01.2025;EMPLOYER SIA;Alga;2500,00;\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	o := config.OutputConfig{Thousands: " "}.WithDefaults()
+	ts, err := readLabeled(path, o)
+	if err != nil {
+		t.Fatalf("readLabeled() unexpected error: %v", err)
+	}
+	if len(ts) != 2 {
+		t.Fatalf("readLabeled() returned %d transactions, want 2", len(ts))
+	}
+	if got := ts[0]; got.AccountHolder != "MAXIMA LV" || got.Description != "PIRKUMS; MAXIMA" || got.Value != -105161 || got.Category != "Groceries" {
+		t.Errorf("readLabeled()[0] = %+v", got)
+	}
+	if got := ts[1]; got.Value != 250000 || got.Category != "" {
+		t.Errorf("readLabeled()[1] = %+v", got)
+	}
+
+	if err := os.WriteFile(path, []byte("date;value\n01.01.2025;1,00\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readLabeled(path, o); err == nil {
+		t.Error("readLabeled() expected an error without a category column")
+	}
+}
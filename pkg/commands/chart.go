@@ -0,0 +1,225 @@
+package commands
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"statements/pkg/analysis"
+	"statements/pkg/chart"
+	"statements/pkg/config"
+	"statements/pkg/transactions"
+
+	"github.com/spf13/cobra"
+)
+
+// The charts the chart command draws.
+var chartKinds = []string{"categories", "monthly", "balance"}
+
+func NewChartCommand() *cobra.Command {
+	var history historyFlags
+	var width, height *int
+	var ascii *bool
+	var currency, confile *string
+
+	cmd := &cobra.Command{
+		Use:   "chart {categories|monthly|balance}",
+		Short: "Draw charts of bank statements in the terminal",
+		Long: "Draw charts of bank statements in the terminal: a bar chart of spending by category, " +
+			"sparklines of monthly spending by category, or a line chart of the running balance.",
+		Args:      cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+		ValidArgs: chartKinds,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := config.Parse(*confile)
+			if err != nil {
+				return fmt.Errorf("could not parse config file: %v", err)
+			}
+
+			cs := chart.Detect()
+			if *ascii {
+				cs = chart.ASCII
+			}
+			if *width <= 0 {
+				*width = terminalWidth()
+			}
+
+			seq, err := history.load(cmd.ErrOrStderr(), c, args[0] == "balance")
+			if err != nil {
+				return err
+			}
+			ts, err := transactions.Collect(seq)
+			if err != nil {
+				return err
+			}
+			if *currency == "" {
+				*currency = mainCurrency(ts)
+			}
+
+			out := cmd.OutOrStdout()
+			switch args[0] {
+			case "categories":
+				return chart.Bars(out, categoryBars(ts, *currency), *width, cs)
+			case "monthly":
+				return writeSparklines(out, ts, *currency, cs)
+			default:
+				values, from, to := runningBalance(ts, *currency)
+				if len(values) == 0 {
+					return fmt.Errorf("no %s transactions to chart", *currency)
+				}
+				fmt.Fprintf(out, "Balance in %s\n", *currency)
+				return chart.Line(out, values, from.Format(time.DateOnly), to.Format(time.DateOnly), *width, *height, cs)
+			}
+		},
+	}
+
+	history = addHistoryFlags(cmd)
+
+	width = cmd.Flags().IntP("width", "w", 0, "width of the charts in columns, defaulting to the terminal width")
+
+	height = cmd.Flags().Int("height", 12, "height of line charts in rows")
+
+	ascii = cmd.Flags().Bool("ascii", false, "draw with ASCII characters only, the default if the terminal is not UTF-8")
+
+	currency = cmd.Flags().String("currency", "", "currency to chart, defaulting to the most common one")
+
+	confile = cmd.Flags().String("config", config.DefaultConfig, "configuration file to use")
+
+	return cmd
+}
+
+// The width of the terminal from the `COLUMNS` environment variable, defaulting to 80.
+func terminalWidth() int {
+	if w, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && w > 0 {
+		return w
+	}
+	return 80
+}
+
+// The currency most transactions are in.
+func mainCurrency(ts []transactions.Transaction) string {
+	counts := map[string]int{}
+	currency := ""
+	for _, t := range ts {
+		counts[t.Currency]++
+		if counts[t.Currency] > counts[currency] {
+			currency = t.Currency
+		}
+	}
+	return currency
+}
+
+// Bars of the spending per category, largest first.
+func categoryBars(ts []transactions.Transaction, currency string) []chart.Bar {
+	s := analysis.NewSummary(analysis.GroupCategory)
+	for _, t := range ts {
+		if t.Currency == currency {
+			s.Add(t)
+		}
+	}
+
+	var bars []chart.Bar
+	for _, g := range s.Groups() {
+		if g.Spending < 0 {
+			bars = append(bars, chart.Bar{
+				Label: g.Key,
+				Value: float64(-g.Spending),
+				Text:  fmt.Sprintf("%s %s", amount(-g.Spending), currency),
+			})
+		}
+	}
+	slices.SortStableFunc(bars, func(a, b chart.Bar) int { return cmp.Compare(b.Value, a.Value) })
+	return bars
+}
+
+// Writes sparklines of the monthly spending in total and per category.
+func writeSparklines(w io.Writer, ts []transactions.Transaction, currency string, cs chart.Charset) error {
+	var first, last time.Time
+	spending := map[string]map[string]int{}
+	for _, t := range ts {
+		if t.Currency != currency || !t.IsMovement() || t.Value >= 0 {
+			continue
+		}
+		if first.IsZero() || t.Date.Before(first) {
+			first = t.Date
+		}
+		if t.Date.After(last) {
+			last = t.Date
+		}
+		month := analysis.GroupMonth.Key(t)
+		for _, key := range []string{"Total", analysis.GroupCategory.Key(t)} {
+			if spending[key] == nil {
+				spending[key] = map[string]int{}
+			}
+			spending[key][month] -= t.Value
+		}
+	}
+	if first.IsZero() {
+		return fmt.Errorf("no %s spending to chart", currency)
+	}
+
+	var months []string
+	for m := time.Date(first.Year(), first.Month(), 1, 0, 0, 0, 0, time.UTC); !m.After(last); m = m.AddDate(0, 1, 0) {
+		months = append(months, m.Format("2006-01"))
+	}
+
+	keys := []string{"Total"}
+	for key := range spending {
+		if key != "Total" {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys[1:])
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "%s..%s\t\tAVERAGE\tLAST\n", months[0], months[len(months)-1])
+	for _, key := range keys {
+		values := make([]float64, len(months))
+		total := 0
+		for i, m := range months {
+			values[i] = float64(spending[key][m])
+			total += spending[key][m]
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", key, chart.Sparkline(values, cs),
+			amount(total/len(months)), amount(spending[key][months[len(months)-1]]))
+	}
+	return tw.Flush()
+}
+
+// The balance at the end of each day, starting from the first opening balance of each account.
+//
+// Without opening balances, the balance starts from zero.
+func runningBalance(ts []transactions.Transaction, currency string) ([]float64, time.Time, time.Time) {
+	var values []float64
+	var from, day time.Time
+	opened := map[string]bool{}
+	balance := 0
+	for _, t := range ts {
+		if t.Currency != currency {
+			continue
+		}
+		switch {
+		case t.Kind == transactions.KindOpeningBalance && !opened[t.Account]:
+			opened[t.Account] = true
+			balance += t.Value
+		case t.IsMovement():
+			balance += t.Value
+		default:
+			continue
+		}
+
+		if from.IsZero() {
+			from, day = t.Date, t.Date
+			values = append(values, 0)
+		}
+		for ; day.Before(t.Date); day = day.AddDate(0, 0, 1) {
+			values = append(values, values[len(values)-1])
+		}
+		values[len(values)-1] = float64(balance) / 100
+	}
+	return values, from, day
+}
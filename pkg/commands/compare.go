@@ -0,0 +1,117 @@
+package commands
+
+import (
+	"fmt"
+
+	"statements/pkg/analysis"
+	"statements/pkg/config"
+
+	"github.com/spf13/cobra"
+)
+
+func NewCompareCommand() *cobra.Command {
+	var history historyFlags
+	var groupBy *[]string
+	var period, against, format, confile *string
+
+	cmd := &cobra.Command{
+		Use:   "compare",
+		Short: "Compare income and spending between two periods",
+		Long: "Compare the net flow of two periods by category and counterparty, where negative changes mean more money going out.\n\n" +
+			"Periods are years (2025), quarters (2025-Q3), months (2025-09), weeks (2025-W36), days (05.09.2025) or ranges of days (01.09.2025..15.09.2025).",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := config.Parse(*confile)
+			if err != nil {
+				return fmt.Errorf("could not parse config file: %v", err)
+			}
+
+			f, err := parseReportFormat(*format)
+			if err != nil {
+				return err
+			}
+
+			current, err := analysis.ParsePeriod(*period)
+			if err != nil {
+				return err
+			}
+			previous := current.Previous()
+			if *against != "" {
+				previous, err = analysis.ParsePeriod(*against)
+				if err != nil {
+					return err
+				}
+			}
+
+			groupings := []analysis.Grouping{""}
+			for _, v := range *groupBy {
+				g, err := analysis.ParseGrouping(v)
+				if err != nil {
+					return err
+				}
+				groupings = append(groupings, g)
+			}
+
+			currents := make([]*analysis.Summary, len(groupings))
+			previouses := make([]*analysis.Summary, len(groupings))
+			for i, g := range groupings {
+				currents[i], previouses[i] = analysis.NewSummary(g), analysis.NewSummary(g)
+			}
+
+			seq, err := history.load(cmd.ErrOrStderr(), c, false)
+			if err != nil {
+				return err
+			}
+			for t, err := range seq {
+				if err != nil {
+					return err
+				}
+				// Each period counts its own transactions, so overlapping periods share theirs.
+				for i := range groupings {
+					if current.Contains(t.Date) {
+						currents[i].Add(t)
+					}
+					if previous.Contains(t.Date) {
+						previouses[i].Add(t)
+					}
+				}
+			}
+
+			fmt.Fprintf(cmd.ErrOrStderr(), "Comparing %s against %s\n", current, previous)
+			r := report{columns: []string{"by", "name", "currency", "current", "previous", "change", "percent", "status"}}
+			for i, g := range groupings {
+				by := string(g)
+				if g == "" {
+					by = "total"
+				}
+				for _, d := range analysis.Compare(currents[i], previouses[i]) {
+					status := ""
+					switch {
+					case d.Previous.Count == 0:
+						status = "new"
+					case d.Current.Count == 0:
+						status = "gone"
+					}
+					r.add(by, d.Key, d.Currency, amount(d.Current.Net()), amount(d.Previous.Net()), amount(d.Change()),
+						percentChange(d.Current.Net(), d.Previous.Net()), status)
+				}
+			}
+			return r.write(cmd.OutOrStdout(), f)
+		},
+	}
+
+	history = addHistoryFlags(cmd)
+
+	period = cmd.Flags().StringP("period", "p", "", "period to compare")
+	cmd.MarkFlagRequired("period")
+
+	against = cmd.Flags().StringP("against", "a", "", "period to compare against, defaulting to the period of the same length before")
+
+	groupBy = cmd.Flags().StringSliceP("group-by", "g", []string{string(analysis.GroupCategory), string(analysis.GroupCounterparty)}, fmt.Sprintf("dimensions to break the change down by, any of %v", analysis.Groupings))
+
+	format = cmd.Flags().StringP("format", "f", string(reportTable), fmt.Sprintf("report format, one of %v", reportFormats))
+
+	confile = cmd.Flags().String("config", config.DefaultConfig, "configuration file to use")
+
+	return cmd
+}
@@ -0,0 +1,117 @@
+package commands
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"statements/pkg/analysis"
+	"statements/pkg/config"
+	"statements/pkg/ctime"
+	"statements/pkg/transactions"
+
+	"github.com/spf13/cobra"
+)
+
+func NewForecastCommand() *cobra.Command {
+	var history historyFlags
+	var months *int
+	var balance *float64
+	var interval, currency, date, format, confile *string
+
+	cmd := &cobra.Command{
+		Use:   "forecast",
+		Short: "Project future balances from bank statements",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := config.Parse(*confile)
+			if err != nil {
+				return fmt.Errorf("could not parse config file: %v", err)
+			}
+
+			f, err := parseReportFormat(*format)
+			if err != nil {
+				return err
+			}
+			if *interval != "day" && *interval != "month" {
+				return fmt.Errorf(`unknown interval %q, must be one of "day", "month"`, *interval)
+			}
+
+			opts := analysis.ForecastOptions{Months: *months, Currency: *currency}
+			if *date != "" {
+				if !cmd.Flags().Changed("balance") || *currency == "" {
+					return fmt.Errorf("a start date requires the balance and currency on that date")
+				}
+				opts.Start, err = time.Parse(ctime.LittleEndianDateOnly, *date)
+				if err != nil {
+					return fmt.Errorf("invalid start date %q: %v", *date, err)
+				}
+				opts.Balance = int(math.Round(*balance * 100))
+			}
+
+			seq, err := history.load(cmd.ErrOrStderr(), c, true)
+			if err != nil {
+				return err
+			}
+			ts, err := transactions.Collect(seq)
+			if err != nil {
+				return err
+			}
+
+			points, err := analysis.Forecast(ts, opts)
+			if err != nil {
+				return fmt.Errorf("%v, provide the starting --balance, --currency and --date", err)
+			}
+			if opts.Currency == "" {
+				_, opts.Currency, _, _ = analysis.LatestBalance(ts, "")
+			}
+
+			threshold := int(math.Round(c.Forecast.Threshold * 100))
+			for _, p := range points {
+				if p.Balance < threshold {
+					fmt.Fprintf(cmd.ErrOrStderr(), "Warning: projected balance drops to %s %s on %s, below %s\n",
+						amount(p.Balance), opts.Currency, p.Date.Format(time.DateOnly), amount(threshold))
+					break
+				}
+			}
+
+			if *interval == "month" {
+				points = monthEnds(points)
+			}
+			r := report{columns: []string{"date", "currency", "balance", "low", "high"}}
+			for _, p := range points {
+				r.add(p.Date.Format(time.DateOnly), opts.Currency, amount(p.Balance), amount(p.Low), amount(p.High))
+			}
+			return r.write(cmd.OutOrStdout(), f)
+		},
+	}
+
+	history = addHistoryFlags(cmd)
+
+	months = cmd.Flags().IntP("months", "m", 3, "number of months to project")
+
+	interval = cmd.Flags().String("interval", "month", `interval of the projection, "day" or "month"`)
+
+	currency = cmd.Flags().String("currency", "", "currency to project, defaulting to the currency of the latest statement balance")
+
+	balance = cmd.Flags().Float64("balance", 0, "balance to start from instead of the latest statement balance")
+
+	date = cmd.Flags().String("date", "", "date of the starting balance (DD.MM.YYYY)")
+
+	format = cmd.Flags().StringP("format", "f", string(reportTable), fmt.Sprintf("report format, one of %v", reportFormats))
+
+	confile = cmd.Flags().String("config", config.DefaultConfig, "configuration file to use")
+
+	return cmd
+}
+
+// Keeps the projections at the end of each month and at the end of the forecast.
+func monthEnds(points []analysis.ForecastPoint) []analysis.ForecastPoint {
+	var ends []analysis.ForecastPoint
+	for i, p := range points {
+		if i == len(points)-1 || points[i+1].Date.Month() != p.Date.Month() {
+			ends = append(ends, p)
+		}
+	}
+	return ends
+}
@@ -0,0 +1,126 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"slices"
+
+	"statements/pkg/classify"
+	"statements/pkg/config"
+	"statements/pkg/transactions"
+
+	"github.com/spf13/cobra"
+)
+
+// Flags selecting the transactions an analysis is based on.
+type historyFlags struct {
+	infiles    *[]string
+	jobs       *int
+	ledger     *string
+	fromLedger *bool
+	tags       *[]string
+	transfers  *bool
+}
+
+// Registers the flags selecting the analyzed transactions on a command.
+func addHistoryFlags(cmd *cobra.Command) historyFlags {
+	var f historyFlags
+
+	f.infiles = cmd.Flags().StringArrayP("input", "i", nil, "input file, directory or glob to analyze as [bank=]path, can be repeated")
+	cmd.MarkFlagFilename("input")
+
+	f.jobs = cmd.Flags().IntP("jobs", "j", runtime.NumCPU(), "number of input files to process concurrently")
+
+	f.ledger = cmd.Flags().String("ledger", "", "ledger file to analyze instead of input files")
+
+	f.fromLedger = cmd.Flags().Bool("from-ledger", false, "analyze the configured or default ledger instead of input files")
+
+	f.tags = cmd.Flags().StringSlice("tag", nil, "only analyze transactions with any of these tags, can be repeated")
+
+	f.transfers = cmd.Flags().Bool("transfers", false, "include transfers between own accounts, which are excluded from spending analyses by default")
+
+	return f
+}
+
+// Whether the transactions are read from the ledger instead of input files.
+func (f historyFlags) usesLedger() bool {
+	return *f.ledger != "" || *f.fromLedger
+}
+
+// Streams the transactions to analyze from the ledger if one is selected, or from the input
+// files otherwise, keeping statement balances of input files if `balances` is set.
+//
+// Split transactions are replaced by their split lines, so analyses see each part with its own
+// category. Transfers between own accounts are neither income nor spending and are left out
+// unless selected, except when balances are kept, as they still move money between accounts.
+func (f historyFlags) load(w io.Writer, c config.Config, balances bool) (transactions.Seq, error) {
+	seq, err := f.loadRows(w, c, balances)
+	if err != nil {
+		return nil, err
+	}
+	seq = transactions.ExpandSplits(seq)
+	if balances || *f.transfers {
+		return seq, nil
+	}
+	return withoutTransfers(seq), nil
+}
+
+// Streams the transactions selected like `load`, keeping split transactions as single rows, such
+// as for editing them.
+//
+// Transfers are matched again on ledger transactions, pairing those of accounts imported
+// separately. If tags are selected, only transactions with any of them are kept besides
+// statement rows.
+func (f historyFlags) loadRows(w io.Writer, c config.Config, balances bool) (transactions.Seq, error) {
+	var seq transactions.Seq
+	if !f.usesLedger() {
+		s, err := loadTransactions(w, c, *f.infiles, loadOptions{jobs: *f.jobs, balances: balances})
+		if err != nil {
+			return nil, err
+		}
+		seq = s
+	} else {
+		if len(*f.infiles) > 0 {
+			return nil, fmt.Errorf("input files cannot be analyzed together with the ledger")
+		}
+		l, err := openLedger(*f.ledger, c)
+		if err != nil {
+			return nil, err
+		}
+		seq = classify.NewTransferMatcher(c.Transfers).Apply(transactions.All(l.Transactions()))
+	}
+
+	if len(*f.tags) == 0 {
+		return seq, nil
+	}
+	return withTags(seq, *f.tags), nil
+}
+
+// Keeps the transactions with any of the tags, along with statement rows that do not move money.
+func withTags(seq transactions.Seq, tags []string) transactions.Seq {
+	return func(yield func(transactions.Transaction, error) bool) {
+		for t, err := range seq {
+			if err == nil && t.IsMovement() && !slices.ContainsFunc(tags, t.HasTag) {
+				continue
+			}
+			if !yield(t, err) || err != nil {
+				return
+			}
+		}
+	}
+}
+
+// Leaves out transfers between own accounts.
+func withoutTransfers(seq transactions.Seq) transactions.Seq {
+	return func(yield func(transactions.Transaction, error) bool) {
+		for t, err := range seq {
+			if err == nil && t.IsTransfer() {
+				continue
+			}
+			if !yield(t, err) || err != nil {
+				return
+			}
+		}
+	}
+}
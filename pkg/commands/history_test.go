@@ -0,0 +1,58 @@
+package commands
+
+import (
+	"slices"
+	"testing"
+
+	"statements/pkg/transactions"
+
+	"github.com/spf13/cobra"
+)
+
+func TestWithTags(t *testing.T) {
+	ts := []transactions.Transaction{
+		{ID: "1", Tags: []string{"vacation-2025", "reimbursable"}},
+		{ID: "2", Tags: []string{"work"}},
+		{ID: "3"},
+		{ID: "4", Kind: transactions.KindClosingBalance},
+	}
+
+	got, err := transactions.Collect(withTags(transactions.All(ts), []string{"#reimbursable", "work"}))
+	if err != nil {
+		t.Fatalf("withTags() unexpected error: %v", err)
+	}
+	var ids []string
+	for _, tr := range got {
+		ids = append(ids, tr.ID)
+	}
+	if len(ids) != 3 || ids[0] != "1" || ids[1] != "2" || ids[2] != "4" {
+		t.Errorf("withTags() kept %v, want the tagged transactions and the balance", ids)
+	}
+}
+
+func TestWithoutTransfers(t *testing.T) {
+	ts := []transactions.Transaction{
+		{ID: "1", Value: -50000, Transfer: "2"},
+		{ID: "2", Value: 50000, Transfer: "1"},
+		{ID: "3", Value: -2310},
+	}
+
+	got, err := transactions.Collect(withoutTransfers(transactions.All(ts)))
+	if err != nil {
+		t.Fatalf("withoutTransfers() unexpected error: %v", err)
+	}
+	if len(got) != 1 || got[0].ID != "3" {
+		t.Errorf("withoutTransfers() kept %+v, want only the payment", got)
+	}
+}
+
+func TestHistoryFlags_InputWithComma(t *testing.T) {
+	cmd := &cobra.Command{Use: "test"}
+	f := addHistoryFlags(cmd)
+	if err := cmd.ParseFlags([]string{"-i", "swedbank=statements/2025,01.csv", "-i", "b.csv"}); err != nil {
+		t.Fatalf("ParseFlags() unexpected error: %v", err)
+	}
+	if want := []string{"swedbank=statements/2025,01.csv", "b.csv"}; !slices.Equal(*f.infiles, want) {
+		t.Errorf("input = %q, want %q", *f.infiles, want)
+	}
+}
@@ -0,0 +1,82 @@
+package commands
+
+import (
+	"fmt"
+	"runtime"
+
+	"statements/pkg/config"
+	"statements/pkg/ledger"
+	"statements/pkg/transactions"
+
+	"github.com/spf13/cobra"
+)
+
+func NewImportCommand() *cobra.Command {
+	var infiles *[]string
+	var jobs *int
+	var ledfile, confile *string
+
+	cmd := &cobra.Command{
+		Use:   "import",
+		Short: "Import bank statements into the ledger",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := config.Parse(*confile)
+			if err != nil {
+				return fmt.Errorf("could not parse config file: %v", err)
+			}
+
+			seq, err := loadTransactions(cmd.ErrOrStderr(), c, *infiles, loadOptions{jobs: *jobs})
+			if err != nil {
+				return err
+			}
+
+			ts, err := transactions.Collect(seq)
+			if err != nil {
+				return err
+			}
+
+			l, err := openLedger(*ledfile, c)
+			if err != nil {
+				return err
+			}
+
+			added, skipped := l.Add(ts)
+			if len(added) > 0 {
+				err = l.Save()
+				if err != nil {
+					return err
+				}
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Imported %d transaction(s) into %s, skipped %d already stored\n", len(added), l.Path(), len(skipped))
+			return nil
+		},
+	}
+
+	infiles = cmd.Flags().StringArrayP("input", "i", nil, "input file, directory or glob to import as [bank=]path, can be repeated")
+	cmd.MarkFlagFilename("input")
+
+	jobs = cmd.Flags().IntP("jobs", "j", runtime.NumCPU(), "number of input files to process concurrently")
+
+	ledfile = cmd.Flags().String("ledger", "", "ledger file to import into")
+
+	confile = cmd.Flags().String("config", config.DefaultConfig, "configuration file to use")
+
+	return cmd
+}
+
+// Opens the ledger from the flag, the configuration or the default location, in that order.
+func openLedger(path string, c config.Config) (*ledger.Ledger, error) {
+	if path == "" {
+		path = c.Flags.Ledger
+	}
+	if path == "" {
+		dp, err := ledger.DefaultPath()
+		if err != nil {
+			return nil, err
+		}
+		path = dp
+	}
+
+	return ledger.Open(path)
+}
@@ -0,0 +1,136 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"statements/pkg/config"
+	"statements/pkg/ctime"
+	"statements/pkg/ledger"
+	"statements/pkg/transactions"
+
+	"github.com/spf13/cobra"
+)
+
+func NewLedgerCommand() *cobra.Command {
+	var ledfile, confile, account, from, to *string
+
+	cmd := &cobra.Command{
+		Use:   "ledger",
+		Short: "Inspect the transaction ledger",
+	}
+
+	// Opens the ledger and queries it with the shared flags.
+	query := func() ([]transactions.Transaction, error) {
+		// The configuration only provides the default ledger path, so a missing file is fine.
+		var c config.Config
+		if _, err := os.Stat(*confile); *ledfile == "" && !errors.Is(err, fs.ErrNotExist) {
+			pc, err := config.Parse(*confile)
+			if err != nil {
+				return nil, fmt.Errorf("could not parse config file: %v", err)
+			}
+			c = pc
+		}
+
+		l, err := openLedger(*ledfile, c)
+		if err != nil {
+			return nil, err
+		}
+
+		f, t, err := parseDateRange(*from, *to)
+		if err != nil {
+			return nil, err
+		}
+
+		return l.Query(*account, f, t), nil
+	}
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List stored transactions",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ts, err := query()
+			if err != nil {
+				return err
+			}
+
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "DATE\tACCOUNT\tCOUNTERPARTY\tVALUE\tCURRENCY\tDESCRIPTION")
+			for _, t := range ts {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+					t.Date.Format(ctime.LittleEndianDateOnly),
+					t.Account,
+					t.AccountHolder,
+					transactions.FormatValue(t.Value, "."),
+					t.Currency,
+					t.Description,
+				)
+			}
+			return w.Flush()
+		},
+	}
+
+	statsCmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Summarize stored transactions by account",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ts, err := query()
+			if err != nil {
+				return err
+			}
+
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "ACCOUNT\tCURRENCY\tCOUNT\tFROM\tTO\tINFLOW\tOUTFLOW")
+			for _, s := range ledger.Stats(ts) {
+				fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\t%s\t%s\n",
+					s.Account,
+					s.Currency,
+					s.Count,
+					s.From.Format(ctime.LittleEndianDateOnly),
+					s.To.Format(ctime.LittleEndianDateOnly),
+					transactions.FormatValue(s.Inflow, "."),
+					transactions.FormatValue(s.Outflow, "."),
+				)
+			}
+			return w.Flush()
+		},
+	}
+
+	ledfile = cmd.PersistentFlags().String("ledger", "", "ledger file to inspect")
+	confile = cmd.PersistentFlags().String("config", config.DefaultConfig, "configuration file to use")
+	account = cmd.PersistentFlags().String("account", "", "only include transactions of this account")
+	from = cmd.PersistentFlags().String("from", "", "only include transactions on or after this date (DD.MM.YYYY)")
+	to = cmd.PersistentFlags().String("to", "", "only include transactions on or before this date (DD.MM.YYYY)")
+
+	cmd.AddCommand(listCmd)
+	cmd.AddCommand(statsCmd)
+
+	return cmd
+}
+
+// Parses an optional date range from CLI flags, leaving missing bounds as zero dates.
+func parseDateRange(from, to string) (time.Time, time.Time, error) {
+	var f, t time.Time
+	var err error
+
+	if from != "" {
+		f, err = time.Parse(ctime.LittleEndianDateOnly, from)
+		if err != nil {
+			return f, t, fmt.Errorf("invalid start date %q: %v", from, err)
+		}
+	}
+	if to != "" {
+		t, err = time.Parse(ctime.LittleEndianDateOnly, to)
+		if err != nil {
+			return f, t, fmt.Errorf("invalid end date %q: %v", to, err)
+		}
+	}
+
+	return f, t, nil
+}
@@ -0,0 +1,387 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"iter"
+	"os"
+	"path/filepath"
+	"slices"
+	"statements/pkg/adapters"
+	"statements/pkg/classify"
+	"statements/pkg/config"
+	"statements/pkg/ctime"
+	"statements/pkg/review"
+	"statements/pkg/transactions"
+	"strings"
+)
+
+// An input file together with the bank it was exported from.
+type input struct {
+	path string
+	bank transactions.Bank
+}
+
+// Options of the transaction loading pipeline.
+type loadOptions struct {
+	// The number of input files processed concurrently.
+	jobs int
+	// Whether statement balances are kept regardless of the configured filters.
+	balances bool
+}
+
+// Streams the read, filtered, normalized, deduplicated and classified transactions of all input
+// files.
+//
+// If no input files are provided, the input from the configuration or the bank's default input
+// file is used instead. Files are processed concurrently by up to `opts.jobs` workers and merged
+// in date order, expecting each file to be in ascending or descending date order as exported by
+// the bank. Dropped duplicates are reported to `w` once the stream is exhausted.
+func loadTransactions(w io.Writer, c config.Config, infiles []string, opts loadOptions) (transactions.Seq, error) {
+	var bank transactions.Bank
+	if c.Flags.Bank != "" {
+		err := bank.Set(c.Flags.Bank)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(infiles) == 0 {
+		cInput := c.Flags.Input
+		if cInput != "" {
+			infiles = []string{cInput}
+		} else {
+			bInput, err := bank.Input()
+			if err == nil {
+				infiles = []string{bInput}
+			} else {
+				return nil, fmt.Errorf("no input file provided")
+			}
+		}
+	}
+
+	inputs, err := resolveInputs(infiles, bank)
+	if err != nil {
+		return nil, err
+	}
+
+	extractor, err := classify.NewFieldExtractor(c.Extract)
+	if err != nil {
+		return nil, err
+	}
+	payees, err := classify.NewPayeeNormalizer(c.Payees)
+	if err != nil {
+		return nil, err
+	}
+	// Review edits are only applied from a sidecar file that is configured or passed to the
+	// command, and never from a default file that happens to be in the working directory. Rules
+	// created while reviewing take precedence over configured ones.
+	var sidecar *review.Sidecar
+	var rules []config.CategoryRule
+	if c.Review.Sidecar != "" {
+		sidecar, err = review.OpenSidecar(c.Review.Sidecar)
+		if err != nil {
+			return nil, err
+		}
+		rules = sidecar.Categories
+	}
+	classifier, err := classify.NewClassifier(append(slices.Clone(rules), c.Categories...))
+	if err != nil {
+		return nil, err
+	}
+	tagger, err := classify.NewTagger(c.Tags)
+	if err != nil {
+		return nil, err
+	}
+	transfers := classify.NewTransferMatcher(c.Transfers)
+	var model *classify.ModelClassifier
+	if c.Categorizer.Model != "" {
+		model, err = classify.NewModelClassifier(c.Categorizer)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sem := make(chan struct{}, max(opts.jobs, 1))
+	seqs := make([]transactions.Seq, len(inputs))
+	// The number of transactions moving money the filters removed from each input while keeping
+	// its balances, written by the worker of the input and read once the stream is exhausted.
+	filtered := make([]int, len(inputs))
+	for i, in := range inputs {
+		seqs[i] = prefetch(streamInput(in.path, in.bank, c.Filters, extractor, opts.balances, &filtered[i]), sem)
+	}
+
+	merged := seqs[0]
+	if len(seqs) > 1 {
+		merged = transactions.MergeByDate(seqs...)
+	}
+
+	return func(yield func(transactions.Transaction, error) bool) {
+		var dropped []transactions.Transaction
+		deduped := transactions.DeduplicateSeq(merged, func(t transactions.Transaction) {
+			dropped = append(dropped, t)
+		})
+		classified := payees.Apply(deduped)
+		// Review edits come before classification, so rules only fill in what was left empty.
+		if sidecar != nil {
+			classified = sidecar.Apply(classified)
+		}
+		classified = classifier.Apply(classified)
+		// The model only categorizes transactions no category rule matched.
+		if model != nil {
+			classified = model.Apply(classified)
+		}
+		// Tags removed while reviewing are removed again once tag rules have added theirs.
+		classified = tagger.Apply(classified)
+		if sidecar != nil {
+			classified = sidecar.Untag(classified)
+		}
+		// Transfers are marked last, once tags are added.
+		classified = transfers.Apply(classified)
+		for t, err := range classified {
+			if !yield(t, err) || err != nil {
+				return
+			}
+		}
+		reportDuplicates(w, dropped)
+		reportAdjustedBalances(w, inputs, filtered)
+	}, nil
+}
+
+// Expands input specifications into individual input files.
+//
+// A specification is a file, a directory of CSV files or a glob pattern, optionally prefixed
+// with the bank it was exported from as `bank=path`. Inputs without a bank use `def`.
+func resolveInputs(specs []string, def transactions.Bank) ([]input, error) {
+	var inputs []input
+
+	for _, spec := range specs {
+		bank := def
+		if before, after, ok := strings.Cut(spec, "="); ok {
+			var b transactions.Bank
+			if b.Set(before) == nil {
+				bank = b
+				spec = after
+			}
+		}
+		if bank == "" {
+			return nil, fmt.Errorf("%s: no bank provided", spec)
+		}
+
+		var paths []string
+		if fi, err := os.Stat(spec); err == nil && fi.IsDir() {
+			matches, err := filepath.Glob(filepath.Join(spec, "*.csv"))
+			if err != nil {
+				return nil, fmt.Errorf("%s: %v", spec, err)
+			}
+			if len(matches) == 0 {
+				return nil, fmt.Errorf("%s: directory contains no CSV files", spec)
+			}
+			paths = matches
+		} else if strings.ContainsAny(spec, "*?[") {
+			matches, err := filepath.Glob(spec)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %v", spec, err)
+			}
+			if len(matches) == 0 {
+				return nil, fmt.Errorf("%s: pattern matches no files", spec)
+			}
+			paths = matches
+		} else {
+			paths = []string{spec}
+		}
+
+		for _, p := range paths {
+			inputs = append(inputs, input{path: p, bank: bank})
+		}
+	}
+
+	return inputs, nil
+}
+
+// Streams the filtered and normalized transactions of a single input file, with fields
+// extracted.
+//
+// Filters on fields of the bank are applied to the rows of the file, while filters on fields of
+// normalized transactions, including extracted `metadata.*` fields, are applied once fields are
+// extracted.
+//
+// If `balances` is set, statement balances are kept regardless of the filters. As the filters
+// can remove transactions that move money, balances are reduced by the value of the removed
+// transactions before them, so balance assertions match the transactions that are written, and
+// the number of removed transactions is stored in `filtered`. Only the input itself is taken into
+// account, so the opening balance of a later statement of the same account is not adjusted.
+//
+// Files exported newest first are put in date order.
+func streamInput(infile string, bank transactions.Bank, rfs []config.RawFilter, extractor *classify.FieldExtractor, balances bool, filtered *int) transactions.Seq {
+	return func(yield func(transactions.Transaction, error) bool) {
+		var ts transactions.Seq
+		// The value of the transactions removed by the filters so far.
+		removed := 0
+		var normalized []config.Filter
+
+		switch bank {
+		case "swedbank":
+			fs, nfs, err := decodeInputFilters(rfs, adapters.SwedbankFieldMap)
+			if err != nil {
+				yield(transactions.Transaction{}, err)
+				return
+			}
+			normalized = nfs
+
+			sts := adapters.ParseSwedbankTransactions(readInput(infile))
+			if balances {
+				sts = adapters.FilterKeepingBalances(sts, fs, func(st adapters.SwedbankTransaction) {
+					removed += st.Normalize().Value
+					*filtered++
+				})
+			} else {
+				sts = adapters.Filter(sts, fs)
+			}
+			ts = extractor.Apply(transactions.Chronological(adapters.Normalize(sts)))
+		default:
+			yield(transactions.Transaction{}, fmt.Errorf("unsupported bank %q", bank))
+			return
+		}
+
+		for t, err := range ts {
+			if err != nil {
+				yield(t, fmt.Errorf("%s: %v", infile, err))
+				return
+			}
+			if !t.Matches(normalized) && (!balances || t.IsMovement()) {
+				if balances {
+					removed += t.Value
+					*filtered++
+				}
+				continue
+			}
+			if t.IsBalance() {
+				t.Value -= removed
+			}
+			if t.Metadata == nil {
+				t.Metadata = map[string]string{}
+			}
+			t.Metadata["source"] = infile
+			if !yield(t, nil) {
+				return
+			}
+		}
+	}
+}
+
+// Decodes the filters of the configuration, splitting them into filters on the fields of the bank
+// and filters on the fields of normalized transactions.
+func decodeInputFilters(rfs []config.RawFilter, fields config.FieldMap) ([]config.Filter, []config.Filter, error) {
+	types := func(field string) config.FieldType {
+		if t, ok := fields[field]; ok {
+			return t
+		}
+		return transactions.FieldTypeOf(field)
+	}
+
+	var bank, normalized []config.Filter
+	for _, rf := range rfs {
+		f, err := rf.DecodeWithFieldTypes(types)
+		if err != nil {
+			return nil, nil, err
+		}
+		if _, ok := fields[f.FieldName()]; ok {
+			bank = append(bank, f)
+		} else {
+			normalized = append(normalized, f)
+		}
+	}
+	return bank, normalized, nil
+}
+
+// The number of transactions a worker reads ahead of the consumer.
+const prefetchSize = 1024
+
+// Reads a stream ahead of its consumer in a separate goroutine.
+//
+// The goroutine only holds a slot of `sem` while producing a batch, so any number of streams
+// can be open at once while the amount of concurrent work stays bounded.
+func prefetch(seq transactions.Seq, sem chan struct{}) transactions.Seq {
+	type batch struct {
+		ts  []transactions.Transaction
+		err error
+	}
+
+	return func(yield func(transactions.Transaction, error) bool) {
+		batches := make(chan batch, 1)
+		done := make(chan struct{})
+		defer close(done)
+
+		go func() {
+			defer close(batches)
+
+			next, stop := iter.Pull2(seq)
+			defer stop()
+
+			for {
+				var b batch
+				sem <- struct{}{}
+				for len(b.ts) < prefetchSize {
+					t, err, ok := next()
+					if !ok {
+						break
+					}
+					if err != nil {
+						b.err = err
+						break
+					}
+					b.ts = append(b.ts, t)
+				}
+				<-sem
+
+				select {
+				case batches <- b:
+				case <-done:
+					return
+				}
+				if len(b.ts) < prefetchSize || b.err != nil {
+					return
+				}
+			}
+		}()
+
+		for b := range batches {
+			for _, t := range b.ts {
+				if !yield(t, nil) {
+					return
+				}
+			}
+			if b.err != nil {
+				yield(transactions.Transaction{}, b.err)
+				return
+			}
+		}
+	}
+}
+
+// Reports transactions dropped as duplicates to the provided writer.
+func reportDuplicates(w io.Writer, dropped []transactions.Transaction) {
+	if len(dropped) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "Dropped %d duplicate transaction(s):\n", len(dropped))
+	for _, t := range dropped {
+		fmt.Fprintf(w, "  %s  %s  %s %s  %s (%s)", t.Date.Format(ctime.LittleEndianDateOnly), t.AccountHolder, transactions.FormatValue(t.Value, "."), t.Currency, t.Description, t.ID)
+		if src := t.Metadata["source"]; src != "" {
+			fmt.Fprintf(w, " in %s", src)
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+// Warns about the inputs whose statement balances were adjusted for transactions removed by the
+// filters, as they no longer match the statements of the bank.
+func reportAdjustedBalances(w io.Writer, inputs []input, filtered []int) {
+	for i, n := range filtered {
+		if n > 0 {
+			fmt.Fprintf(w, "Adjusted the balances of %s for %d transaction(s) removed by the filters\n", inputs[i].path, n)
+		}
+	}
+}
@@ -0,0 +1,357 @@
+package commands
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"statements/pkg/config"
+	"statements/pkg/review"
+	"statements/pkg/transactions"
+	"statements/pkg/writers"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestResolveInputs(t *testing.T) {
+	tmpDir := t.TempDir()
+	for _, name := range []string{"a.csv", "b.csv", "notes.txt"} {
+		err := os.WriteFile(filepath.Join(tmpDir, name), []byte{}, 0644)
+		if err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		specs   []string
+		def     transactions.Bank
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:  "plain file",
+			specs: []string{filepath.Join(tmpDir, "a.csv")},
+			def:   transactions.BankSwedbank,
+			want:  []string{filepath.Join(tmpDir, "a.csv")},
+		},
+		{
+			name:  "directory",
+			specs: []string{tmpDir},
+			def:   transactions.BankSwedbank,
+			want:  []string{filepath.Join(tmpDir, "a.csv"), filepath.Join(tmpDir, "b.csv")},
+		},
+		{
+			name:  "glob with bank",
+			specs: []string{"swedbank=" + filepath.Join(tmpDir, "b*.csv")},
+			want:  []string{filepath.Join(tmpDir, "b.csv")},
+		},
+		{
+			name:    "glob without matches",
+			specs:   []string{filepath.Join(tmpDir, "*.xlsx")},
+			def:     transactions.BankSwedbank,
+			wantErr: true,
+		},
+		{
+			name:    "no bank",
+			specs:   []string{filepath.Join(tmpDir, "a.csv")},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveInputs(tt.specs, tt.def)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("resolveInputs() expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveInputs() unexpected error: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("resolveInputs() returned %d inputs, want %d", len(got), len(tt.want))
+			}
+			for i, in := range got {
+				if in.path != tt.want[i] {
+					t.Errorf("resolveInputs()[%d].path = %q, want %q", i, in.path, tt.want[i])
+				}
+				if in.bank != transactions.BankSwedbank {
+					t.Errorf("resolveInputs()[%d].bank = %q, want swedbank", i, in.bank)
+				}
+			}
+		})
+	}
+}
+
+func TestPrefetch(t *testing.T) {
+	var ts []transactions.Transaction
+	for i := range prefetchSize*2 + 10 {
+		ts = append(ts, transactions.Transaction{Value: i})
+	}
+
+	sem := make(chan struct{}, 1)
+	got, err := transactions.Collect(prefetch(transactions.All(ts), sem))
+	if err != nil {
+		t.Fatalf("prefetch() unexpected error: %v", err)
+	}
+	if len(got) != len(ts) {
+		t.Fatalf("prefetch() returned %d transactions, want %d", len(got), len(ts))
+	}
+	for i, tx := range got {
+		if tx.Value != i {
+			t.Fatalf("prefetch()[%d].Value = %d, want %d", i, tx.Value, i)
+		}
+	}
+
+	// Stopping early must not leak the producer or deadlock.
+	for range prefetch(transactions.All(ts), sem) {
+		break
+	}
+}
+
+func TestLoadTransactions_FilteredBalances(t *testing.T) {
+	tmpDir := t.TempDir()
+	infile := filepath.Join(tmpDir, "statement.csv")
+	content := `"Klienta konts";"Ieraksta tips";"Datums";"Saņēmējs/Maksātājs";"Informācija saņēmējam";"Summa";"Valūta";"Debets/Kredīts";"Arhīva kods";"Maksājuma veids";"Refernces numurs";"Dokumenta numurs"
+"LV02HABA0123456789012";"10";"01.01.2025";"";"Sākuma atlikums";"1000,00";"EUR";"K";"";"AS";"";""
+"LV02HABA0123456789012";"20";"02.01.2025";"MAXIMA LV";"PIRKUMS";"20,00";"EUR";"D";"A1";"PRV";"";""
+"LV02HABA0123456789012";"20";"03.01.2025";"LANDLORD";"Rent";"700,00";"EUR";"D";"A2";"PRV";"";""
+"LV02HABA0123456789012";"86";"31.01.2025";"";"Beigu atlikums";"280,00";"EUR";"K";"";"AS";"";""
+`
+	if err := os.WriteFile(infile, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var filters []config.RawFilter
+	if err := json.Unmarshal([]byte(`[
+		{"field": "Ieraksta tips", "condition": "EQUAL", "comparison": "20"},
+		{"field": "Saņēmējs/Maksātājs", "condition": "NOT_EQUAL", "comparison": "LANDLORD"}
+	]`), &filters); err != nil {
+		t.Fatal(err)
+	}
+	c := config.Config{
+		Flags:   config.FlagConfig{Bank: "swedbank"},
+		Filters: filters,
+		Review:  config.ReviewConfig{Sidecar: filepath.Join(tmpDir, "review.json")},
+	}
+
+	var warnings bytes.Buffer
+	outfile := filepath.Join(tmpDir, "output.beancount")
+	err := writeOutput(outfile, writers.FormatBeancount, c, func(balances bool) (transactions.Seq, error) {
+		return loadTransactions(&warnings, c, []string{infile}, loadOptions{jobs: 1, balances: balances})
+	})
+	if err != nil {
+		t.Fatalf("writeOutput() unexpected error: %v", err)
+	}
+	out, err := os.ReadFile(outfile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The closing balance is reduced by the filtered rent, matching the opening balance and the
+	// purchase that are written.
+	if !bytes.Contains(out, []byte("1000.00 EUR")) || !bytes.Contains(out, []byte("980.00 EUR")) || bytes.Contains(out, []byte("LANDLORD")) {
+		t.Errorf("beancount output does not assert the adjusted balance:\n%s", out)
+	}
+	if !strings.Contains(warnings.String(), "for 1 transaction(s) removed by the filters") {
+		t.Errorf("warnings = %q, want the adjusted balances reported", warnings.String())
+	}
+}
+
+func TestLoadTransactions_ExtractedFieldFilters(t *testing.T) {
+	tmpDir := t.TempDir()
+	infile := filepath.Join(tmpDir, "statement.csv")
+	content := `"Klienta konts";"Ieraksta tips";"Datums";"Saņēmējs/Maksātājs";"Informācija saņēmējam";"Summa";"Valūta";"Debets/Kredīts";"Arhīva kods";"Maksājuma veids";"Refernces numurs";"Dokumenta numurs"
+"LV02HABA0123456789012";"10";"01.01.2025";"";"Sākuma atlikums";"1000,00";"EUR";"K";"";"AS";"";""
+"LV02HABA0123456789012";"20";"02.01.2025";"MAXIMA LV";"PIRKUMS 4111 MAXIMA";"20,00";"EUR";"D";"A1";"PRV";"";""
+"LV02HABA0123456789012";"20";"03.01.2025";"LANDLORD";"Rent";"700,00";"EUR";"D";"A2";"PRV";"";""
+"LV02HABA0123456789012";"86";"31.01.2025";"";"Beigu atlikums";"280,00";"EUR";"K";"";"AS";"";""
+`
+	if err := os.WriteFile(infile, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var filters []config.RawFilter
+	if err := json.Unmarshal([]byte(`[
+		{"field": "Ieraksta tips", "condition": "NOT_EQUAL", "comparison": "30"},
+		{"field": "metadata.card", "condition": "EQUAL", "comparison": ""}
+	]`), &filters); err != nil {
+		t.Fatal(err)
+	}
+	c := config.Config{
+		Flags:   config.FlagConfig{Bank: "swedbank"},
+		Filters: filters,
+		Extract: []config.ExtractRule{{Pattern: `PIRKUMS (?P<card>\d+)`}},
+	}
+
+	seq, err := loadTransactions(io.Discard, c, []string{infile}, loadOptions{jobs: 1, balances: true})
+	if err != nil {
+		t.Fatalf("loadTransactions() unexpected error: %v", err)
+	}
+	ts, err := transactions.Collect(seq)
+	if err != nil {
+		t.Fatalf("loadTransactions() unexpected error: %v", err)
+	}
+
+	// The card purchase is removed by the filter on the extracted card number, and the closing
+	// balance is adjusted for it.
+	var got []string
+	for _, tr := range ts {
+		got = append(got, fmt.Sprintf("%s %d", tr.Kind, tr.Value))
+	}
+	want := []string{"opening_balance 100000", "transaction -70000", "closing_balance 30000"}
+	if !slices.Equal(got, want) {
+		t.Errorf("loadTransactions() = %v, want %v", got, want)
+	}
+}
+
+func TestLoadTransactions_Sidecar(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Chdir(tmpDir)
+	content := `"Klienta konts";"Ieraksta tips";"Datums";"Saņēmējs/Maksātājs";"Informācija saņēmējam";"Summa";"Valūta";"Debets/Kredīts";"Arhīva kods";"Maksājuma veids";"Refernces numurs";"Dokumenta numurs"
+"LV02HABA0123456789012";"20";"02.01.2025";"MAXIMA LV";"PIRKUMS";"20,00";"EUR";"D";"A1";"PRV";"";""
+`
+	if err := os.WriteFile("statement.csv", []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	load := func(c config.Config) transactions.Transaction {
+		seq, err := loadTransactions(io.Discard, c, []string{"swedbank=statement.csv"}, loadOptions{jobs: 1})
+		if err != nil {
+			t.Fatalf("loadTransactions() unexpected error: %v", err)
+		}
+		ts, err := transactions.Collect(seq)
+		if err != nil || len(ts) != 1 {
+			t.Fatalf("loadTransactions() = %v, %v, want a single transaction", ts, err)
+		}
+		return ts[0]
+	}
+
+	// Edits stored in the default file are only applied when it is configured.
+	tr := load(config.Config{})
+	sidecar, err := review.OpenSidecar(review.DefaultSidecar)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edited := tr
+	edited.Category = "Groceries"
+	sidecar.Record(tr, edited)
+	if err := sidecar.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if got := load(config.Config{}); got.Category != "" {
+		t.Errorf("category without a configured sidecar = %q, want none", got.Category)
+	}
+	if got := load(config.Config{Review: config.ReviewConfig{Sidecar: review.DefaultSidecar}}); got.Category != "Groceries" {
+		t.Errorf("category with a configured sidecar = %q, want Groceries", got.Category)
+	}
+
+	// A tag added by a rule and removed while reviewing stays removed.
+	c := config.Config{
+		Tags:   []config.TagRule{{Tags: []string{"vacation"}, Filters: []config.RawFilter{{Raw: []byte(`{"field":"currency","condition":"EQUAL","comparison":"EUR"}`)}}}},
+		Review: config.ReviewConfig{Sidecar: review.DefaultSidecar},
+	}
+	tagged := load(c)
+	if !tagged.HasTag("vacation") {
+		t.Fatalf("tags = %v, want the tag of the rule", tagged.Tags)
+	}
+	untagged := tagged
+	untagged.Tags = transactions.RemoveTags(tagged.Tags, "vacation")
+	sidecar.Record(tagged, untagged)
+	if err := sidecar.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if got := load(c); got.HasTag("vacation") || got.Category != "Groceries" {
+		t.Errorf("tags = %v and category = %q, want the tag removed and the category kept", got.Tags, got.Category)
+	}
+}
+
+// The number of rows in the generated benchmark statement.
+const benchRows = 2_000_000
+
+// Writes a Swedbank statement with the provided number of transaction rows.
+func writeBenchStatement(b *testing.B, path string, rows int) {
+	f, err := os.Create(path)
+	if err != nil {
+		b.Fatalf("Failed to create benchmark file: %v", err)
+	}
+	defer f.Close()
+
+	w := bufio.NewWriter(f)
+	fmt.Fprintln(w, `"Klienta konts";"Ieraksta tips";"Datums";"Saņēmējs/Maksātājs";"Informācija saņēmējam";"Summa";"Valūta";"Debets/Kredīts";"Arhīva kods";"Maksājuma veids";"Refernces numurs";"Dokumenta numurs"`)
+	start := time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := range rows {
+		date := start.AddDate(0, 0, i/1000).Format("02.01.2006")
+		flow := "D"
+		if i%7 == 0 {
+			flow = "K"
+		}
+		fmt.Fprintf(w, "\"LV02HABA0123456789012\";\"20\";\"%s\";\"SHOP %d\";\"PURCHASE %d\";\"%d,%02d\";\"EUR\";\"%s\";\"%016d\";\"PRV\";\"\";\"\"\n",
+			date, i%500, i, i%1000, i%100, flow, i)
+	}
+	if err := w.Flush(); err != nil {
+		b.Fatalf("Failed to write benchmark file: %v", err)
+	}
+}
+
+// Measures the full process pipeline over a multi-million row statement, reporting the peak
+// heap usage to show that memory does not grow with the input.
+func BenchmarkProcessPipeline(b *testing.B) {
+	tmpDir := b.TempDir()
+	infile := filepath.Join(tmpDir, "statement.csv")
+	outfile := filepath.Join(tmpDir, "output.csv")
+	writeBenchStatement(b, infile, benchRows)
+
+	c := config.Config{Flags: config.FlagConfig{Bank: "swedbank"}}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	var peak uint64
+	for b.Loop() {
+		runtime.GC()
+
+		n := 0
+		load := func(balances bool) (transactions.Seq, error) {
+			seq, err := loadTransactions(io.Discard, c, []string{infile}, loadOptions{jobs: 1, balances: balances})
+			if err != nil {
+				return nil, err
+			}
+			return sample(seq, &n, &peak), nil
+		}
+
+		if err := writeOutput(outfile, writers.FormatCsv, c, load); err != nil {
+			b.Fatalf("writeOutput() unexpected error: %v", err)
+		}
+		if n != benchRows {
+			b.Fatalf("pipeline produced %d transactions, want %d", n, benchRows)
+		}
+	}
+
+	b.ReportMetric(float64(peak)/(1<<20), "peak-heap-MB")
+}
+
+// Counts the transactions of a stream, sampling the heap usage into `peak` periodically.
+func sample(seq transactions.Seq, n *int, peak *uint64) transactions.Seq {
+	return func(yield func(transactions.Transaction, error) bool) {
+		for t, err := range seq {
+			if *n++; *n%250_000 == 0 {
+				var ms runtime.MemStats
+				runtime.ReadMemStats(&ms)
+				*peak = max(*peak, ms.HeapInuse)
+			}
+			if !yield(t, err) {
+				return
+			}
+		}
+	}
+}
@@ -3,17 +3,24 @@ package commands
 import (
 	"encoding/csv"
 	"fmt"
+	"io"
+	"iter"
 	"os"
+	"path/filepath"
+	"runtime"
 
-	"statements/pkg/adapters"
 	"statements/pkg/config"
 	"statements/pkg/transactions"
+	"statements/pkg/writers"
 
 	"github.com/spf13/cobra"
 )
 
 func NewProcessCommand() *cobra.Command {
-	var infile, outfile, confile *string
+	var infiles *[]string
+	var jobs *int
+	var splits *bool
+	var outfile, format, confile *string
 
 	cmd := &cobra.Command{
 		Use:   "process",
@@ -24,70 +31,33 @@ func NewProcessCommand() *cobra.Command {
 				return fmt.Errorf("could not parse config file: %v", err)
 			}
 
-			var bank transactions.Bank
-			err = bank.Set(c.Flags.Bank)
-			if err != nil {
-				return err
-			}
-
-			if *infile == "" {
-				cInput := c.Flags.Input
-				if cInput != "" {
-					*infile = cInput
+			if *outfile == "" {
+				cOutput := c.Flags.Output
+				if cOutput != "" {
+					*outfile = cOutput
 				} else {
-					bInput, err := bank.Input()
-					if err == nil {
-						*infile = bInput
-					} else {
-						return fmt.Errorf("no input file provided")
-					}
+					*outfile = "output.csv"
 				}
 			}
 
-			records, err := readInput(*infile)
-			if err != nil {
-				return err
+			if cmd.Flags().Changed("splits") {
+				c.Output.Splits = *splits
 			}
 
-			var bts []adapters.TransactionAdapter
-			var fs []config.Filter
-
-			switch bank {
-			case "swedbank":
-				sts, err := adapters.NewSwedbankTransactions(records)
+			var f writers.Format
+			if *format == "" {
+				*format = c.Flags.Format
+			}
+			if *format != "" {
+				f, err = writers.ParseFormat(*format)
 				if err != nil {
 					return err
 				}
-				bts = adapters.AdaptTransactions(sts)
-
-				for _, rf := range c.Filters {
-					f, err := rf.DecodeWithFieldMap(adapters.SwedbankFieldMap)
-					if err != nil {
-						return err
-					}
-					fs = append(fs, f)
-				}
-			}
-
-			bts = adapters.FilterTransactions(bts, fs)
-
-			var ts []transactions.Transaction
-			for _, bt := range bts {
-				ts = append(ts, bt.Normalize())
-			}
-
-			// TODO: Add classification here
-
-			if *outfile == "" {
-				cOutput := c.Flags.Output
-				if cOutput != "" {
-					*outfile = cOutput
-				} else {
-					*outfile = "output.csv"
-				}
 			}
 
-			err = writeOutput(*outfile, ts)
+			err = writeOutput(*outfile, f, c, func(balances bool) (transactions.Seq, error) {
+				return loadTransactions(cmd.ErrOrStderr(), c, *infiles, loadOptions{jobs: *jobs, balances: balances})
+			})
 			if err != nil {
 				return err
 			}
@@ -96,55 +66,102 @@ func NewProcessCommand() *cobra.Command {
 		},
 	}
 
-	infile = cmd.Flags().StringP("input", "i", "", "input file to process")
+	infiles = cmd.Flags().StringArrayP("input", "i", nil, "input file, directory or glob to process as [bank=]path, can be repeated")
 	cmd.MarkFlagFilename("input")
 
+	jobs = cmd.Flags().IntP("jobs", "j", runtime.NumCPU(), "number of input files to process concurrently")
+
 	outfile = cmd.Flags().StringP("output", "o", "", "output file to write to")
 
+	splits = cmd.Flags().Bool("splits", false, "write the lines of split transactions instead of the original transactions, overriding the configuration")
+
+	format = cmd.Flags().StringP("format", "f", "", fmt.Sprintf("output format, one of %v, defaulting to the output file extension", writers.Formats()))
+
 	confile = cmd.Flags().String("config", config.DefaultConfig, "configuration file to use")
 
 	return cmd
 }
 
-// Reads the provided input file, returning a parsed CSV if successful.
-func readInput(input string) ([][]string, error) {
-	records := [][]string{}
+// Streams the rows of the provided input file as a parsed CSV.
+func readInput(input string) iter.Seq2[[]string, error] {
+	return func(yield func([]string, error) bool) {
+		in, err := os.Open(input)
+		if err != nil {
+			yield(nil, fmt.Errorf("input file could not be opened: %v", err))
+			return
+		}
+		defer in.Close()
+
+		r := csv.NewReader(in)
+		r.Comma = ';'
+		for {
+			record, err := r.Read()
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				yield(nil, fmt.Errorf("csv file could not be read: %v", err))
+				return
+			}
+			if !yield(record, nil) {
+				return
+			}
+		}
+	}
+}
 
-	in, err := os.Open(input)
-	if err != nil {
-		return records, fmt.Errorf("input file could not be opened: %v", err)
+// Loads the transactions to write, keeping statement balances if `balances` is set.
+type loader func(balances bool) (transactions.Seq, error)
+
+// Writes the loaded transactions to the provided output file.
+//
+// If `format` is empty, the format is resolved from the file extension. Statement balances are
+// only loaded if the writer records them, and split transactions are written as their lines if
+// the output configuration asks for splits.
+func writeOutput(output string, format writers.Format, c config.Config, load loader) error {
+	if format == "" {
+		format = writers.FormatFromPath(output)
 	}
-	defer in.Close()
 
-	r := csv.NewReader(in)
-	r.Comma = ';'
-	records, err = r.ReadAll()
+	// The output is written to a temporary file next to it and only replaces an existing file
+	// once all transactions are written, so failing inputs or rules leave it untouched.
+	out, err := os.CreateTemp(filepath.Dir(output), "."+filepath.Base(output)+".*")
 	if err != nil {
-		return records, fmt.Errorf("csv file could not be read: %v", err)
+		return fmt.Errorf("output file could not be opened: %v", err)
 	}
+	defer os.Remove(out.Name())
+	defer out.Close()
 
-	return records, nil
-}
-
-// Writes transactions to the provided output file as CSV.
-func writeOutput(output string, transactions []transactions.Transaction) error {
-	var rows [][]string
-	for _, t := range transactions {
-		rows = append(rows, t.Csv())
+	if err := write(out, format, c, load); err != nil {
+		return err
+	}
+	if err := out.Chmod(0o644); err != nil {
+		return fmt.Errorf("output file could not be written: %v", err)
+	}
+	if err := out.Close(); err != nil {
+		return fmt.Errorf("output file could not be written: %v", err)
+	}
+	if err := os.Rename(out.Name(), output); err != nil {
+		return fmt.Errorf("output file could not be written: %v", err)
 	}
+	return nil
+}
 
-	out, err := os.Create(output)
+// Writes the loaded transactions to `out` in the provided format.
+func write(out io.Writer, format writers.Format, c config.Config, load loader) error {
+	w, err := writers.New(format, out, c)
 	if err != nil {
-		return fmt.Errorf("output file could not be opened: %v", err)
+		return err
 	}
-	defer out.Close()
 
-	w := csv.NewWriter(out)
-	w.Comma = ';'
-	err = w.WriteAll(rows)
+	_, balances := w.(writers.BalanceWriter)
+	ts, err := load(balances)
 	if err != nil {
-		return fmt.Errorf("csv file could not be written: %v", err)
+		return err
+	}
+	if c.Output.Splits {
+		ts = transactions.ExpandSplits(ts)
 	}
 
-	return nil
+	return writers.WriteAll(w, ts)
 }
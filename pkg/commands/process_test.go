@@ -1,8 +1,13 @@
 package commands
 
 import (
+	"bytes"
+	"errors"
+	"iter"
 	"os"
 	"path/filepath"
+	"slices"
+	"statements/pkg/config"
 	"statements/pkg/transactions"
 	"testing"
 	"time"
@@ -64,7 +69,7 @@ Value4;Value5;Value6`,
 				}
 			}
 
-			records, err := readInput(filePath)
+			records, err := collectRows(readInput(filePath))
 
 			if tt.wantErr {
 				if err == nil {
@@ -97,7 +102,7 @@ Value1;Value2;Value3`
 		t.Fatalf("Failed to create test file: %v", err)
 	}
 
-	records, err := readInput(filePath)
+	records, err := collectRows(readInput(filePath))
 	if err != nil {
 		t.Fatalf("readInput() unexpected error: %v", err)
 	}
@@ -179,7 +184,7 @@ func TestWriteOutput(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			filePath := filepath.Join(tmpDir, "output.csv")
 
-			err := writeOutput(filePath, tt.transactions)
+			err := writeOutput(filePath, "", config.Config{}, loadSlice(tt.transactions))
 
 			if tt.wantErr {
 				if err == nil {
@@ -216,13 +221,13 @@ func TestWriteOutput_ValidateContent(t *testing.T) {
 		},
 	}
 
-	err := writeOutput(filePath, txs)
+	err := writeOutput(filePath, "", config.Config{}, loadSlice(txs))
 	if err != nil {
 		t.Fatalf("writeOutput() unexpected error: %v", err)
 	}
 
 	// Read back the file and validate
-	records, err := readInput(filePath)
+	records, err := collectRows(readInput(filePath))
 	if err != nil {
 		t.Fatalf("Failed to read output file: %v", err)
 	}
@@ -232,8 +237,8 @@ func TestWriteOutput_ValidateContent(t *testing.T) {
 	}
 
 	row := records[0]
-	if len(row) != 5 {
-		t.Errorf("Output row has %d columns, want 5", len(row))
+	if len(row) != 15 {
+		t.Errorf("Output row has %d columns, want 15", len(row))
 	}
 
 	// Validate the date
@@ -251,9 +256,9 @@ func TestWriteOutput_ValidateContent(t *testing.T) {
 		t.Errorf("Description = %q, want Salary payment", row[2])
 	}
 
-	// Validate value (should be formatted as 1500,0)
-	if row[3] != "1500,0" {
-		t.Errorf("Value = %q, want 1500,0", row[3])
+	// Validate value (should be formatted as 1500,00)
+	if row[3] != "1500,00" {
+		t.Errorf("Value = %q, want 1500,00", row[3])
 	}
 
 	// Validate currency
@@ -276,12 +281,115 @@ func TestWriteOutput_InvalidDirectory(t *testing.T) {
 		},
 	}
 
-	err := writeOutput(filePath, txs)
+	err := writeOutput(filePath, "", config.Config{}, loadSlice(txs))
 	if err == nil {
 		t.Error("writeOutput() expected error for invalid directory but got none")
 	}
 }
 
+func TestWriteOutput_FailedLoad(t *testing.T) {
+	tmpDir := t.TempDir()
+	filePath := filepath.Join(tmpDir, "output.csv")
+	if err := os.WriteFile(filePath, []byte("previous output\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	err := writeOutput(filePath, "", config.Config{}, func(bool) (transactions.Seq, error) {
+		return nil, errors.New("no input file provided")
+	})
+	if err == nil {
+		t.Fatal("writeOutput() expected an error when loading fails")
+	}
+	if content, _ := os.ReadFile(filePath); string(content) != "previous output\n" {
+		t.Errorf("writeOutput() replaced the existing output with %q", content)
+	}
+	if entries, _ := os.ReadDir(tmpDir); len(entries) != 1 {
+		t.Errorf("writeOutput() left %d files behind, want only the output", len(entries))
+	}
+}
+
+func TestReportDuplicates(t *testing.T) {
+	var buf bytes.Buffer
+	reportDuplicates(&buf, nil)
+	if buf.Len() != 0 {
+		t.Errorf("reportDuplicates() wrote %q for no duplicates, want nothing", buf.String())
+	}
+
+	reportDuplicates(&buf, []transactions.Transaction{
+		{
+			ID:            "2025010101234567",
+			Date:          time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			AccountHolder: "Test",
+			Description:   "Duplicate",
+			Value:         -150,
+			Currency:      "EUR",
+		},
+	})
+	out := buf.String()
+	if !contains(out, "Dropped 1 duplicate") || !contains(out, "2025010101234567") {
+		t.Errorf("reportDuplicates() = %q, want a summary with the dropped ID", out)
+	}
+}
+
+func TestWriteOutput_Splits(t *testing.T) {
+	txs := []transactions.Transaction{
+		{
+			ID:       "R1",
+			Date:     time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC),
+			Value:    -5161,
+			Currency: "EUR",
+			Splits: []transactions.Split{
+				{Category: "Groceries", Value: -3861},
+				{Category: "Household", Value: -1300},
+			},
+		},
+	}
+
+	for _, tt := range []struct {
+		splits bool
+		want   []string
+	}{
+		{false, []string{"-51,61"}},
+		{true, []string{"-38,61", "-13,00"}},
+	} {
+		filePath := filepath.Join(t.TempDir(), "output.csv")
+		c := config.Config{Output: config.OutputConfig{Splits: tt.splits}}
+		if err := writeOutput(filePath, "", c, loadSlice(txs)); err != nil {
+			t.Fatalf("writeOutput() unexpected error: %v", err)
+		}
+		records, err := collectRows(readInput(filePath))
+		if err != nil {
+			t.Fatalf("Failed to read output file: %v", err)
+		}
+		var got []string
+		for _, r := range records {
+			got = append(got, r[3])
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("writeOutput() with splits %v wrote values %v, want %v", tt.splits, got, tt.want)
+		}
+	}
+}
+
+// Helper function to load a fixed slice of transactions
+func loadSlice(ts []transactions.Transaction) loader {
+	return func(balances bool) (transactions.Seq, error) {
+		return transactions.All(ts), nil
+	}
+}
+
+// Helper function to collect a stream of CSV rows
+func collectRows(rows iter.Seq2[[]string, error]) ([][]string, error) {
+	var records [][]string
+	for row, err := range rows {
+		if err != nil {
+			return records, err
+		}
+		records = append(records, row)
+	}
+	return records, nil
+}
+
 // Helper function to check if a string contains a substring
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) && (s == substr || len(substr) == 0 || hasSubstring(s, substr))
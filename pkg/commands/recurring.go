@@ -0,0 +1,112 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"statements/pkg/analysis"
+	"statements/pkg/config"
+	"statements/pkg/transactions"
+
+	"github.com/spf13/cobra"
+)
+
+func NewRecurringCommand() *cobra.Command {
+	var history historyFlags
+	var occurrences *int
+	var tolerance *float64
+	var format, confile *string
+
+	cmd := &cobra.Command{
+		Use:   "recurring",
+		Short: "Find recurring payments and subscriptions in bank statements",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if *occurrences < 2 {
+				return fmt.Errorf("invalid minimum number of occurrences %d: must be at least 2", *occurrences)
+			}
+
+			c, err := config.Parse(*confile)
+			if err != nil {
+				return fmt.Errorf("could not parse config file: %v", err)
+			}
+
+			f, err := parseReportFormat(*format)
+			if err != nil {
+				return err
+			}
+
+			seq, err := history.load(cmd.ErrOrStderr(), c, false)
+			if err != nil {
+				return err
+			}
+			ts, err := transactions.Collect(seq)
+			if err != nil {
+				return err
+			}
+
+			rs := analysis.FindRecurring(ts, analysis.RecurringOptions{
+				MinOccurrences: *occurrences,
+				Tolerance:      *tolerance,
+			})
+			return recurringReport(rs).write(cmd.OutOrStdout(), f)
+		},
+	}
+
+	history = addHistoryFlags(cmd)
+
+	occurrences = cmd.Flags().Int("min-occurrences", 3, "minimum number of occurrences of a recurring payment, at least 2")
+
+	tolerance = cmd.Flags().Float64("tolerance", 0.2, "relative difference up to which amounts are considered similar")
+
+	format = cmd.Flags().StringP("format", "f", string(reportTable), fmt.Sprintf("report format, one of %v", reportFormats))
+
+	confile = cmd.Flags().String("config", config.DefaultConfig, "configuration file to use")
+
+	return cmd
+}
+
+// Builds a report with a row per recurring payment.
+func recurringReport(rs []analysis.Recurring) report {
+	r := report{columns: []string{"counterparty", "currency", "interval", "count", "average", "last", "next", "missed", "changes"}}
+	for _, rec := range rs {
+		r.add(
+			rec.Counterparty,
+			rec.Currency,
+			string(rec.Interval),
+			rec.Count,
+			amount(rec.Average),
+			rec.Last.Format(time.DateOnly),
+			rec.Next.Format(time.DateOnly),
+			rec.Missed,
+			priceChanges(rec.Changes),
+		)
+	}
+	return r
+}
+
+// Price changes of a recurring payment, printed as a list of dated changes.
+type priceChanges []analysis.PriceChange
+
+func (cs priceChanges) String() string {
+	s := make([]string, len(cs))
+	for i, c := range cs {
+		s[i] = fmt.Sprintf("%s %s -> %s", c.Date.Format(time.DateOnly), amount(c.From), amount(c.To))
+	}
+	return strings.Join(s, "; ")
+}
+
+func (cs priceChanges) MarshalJSON() ([]byte, error) {
+	type change struct {
+		Date string `json:"date"`
+		From amount `json:"from"`
+		To   amount `json:"to"`
+	}
+	out := make([]change, len(cs))
+	for i, c := range cs {
+		out[i] = change{c.Date.Format(time.DateOnly), amount(c.From), amount(c.To)}
+	}
+	return json.Marshal(out)
+}
@@ -0,0 +1,158 @@
+package commands
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"statements/pkg/transactions"
+)
+
+// The formats analysis reports are printed in.
+type reportFormat string
+
+const (
+	reportTable reportFormat = "table"
+	reportCsv   reportFormat = "csv"
+	reportJson  reportFormat = "json"
+)
+
+var reportFormats = []reportFormat{reportTable, reportCsv, reportJson}
+
+// Parses a string into a report format.
+func parseReportFormat(v string) (reportFormat, error) {
+	f := reportFormat(strings.ToLower(v))
+	switch f {
+	case reportTable, reportCsv, reportJson:
+		return f, nil
+	default:
+		return "", fmt.Errorf("unknown report format %q, must be one of %v", v, reportFormats)
+	}
+}
+
+// A tabular analysis report.
+//
+// Cells are strings, integers or amounts, with amounts written as JSON numbers.
+type report struct {
+	// The column names, used as JSON keys and upper-cased as table headers.
+	columns []string
+	rows    [][]any
+}
+
+// An amount in minor units, printed as a decimal number.
+type amount int
+
+func (a amount) String() string {
+	return transactions.FormatValue(int(a), ".")
+}
+
+func (a amount) MarshalJSON() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// A relative change, printed as a percentage and left empty if it is undefined.
+type percent struct {
+	value float64
+	ok    bool
+}
+
+// The change from `previous` to `current` relative to the size of `previous`.
+func percentChange(current, previous int) percent {
+	if previous == 0 {
+		return percent{}
+	}
+	return percent{float64(current-previous) / math.Abs(float64(previous)) * 100, true}
+}
+
+func (p percent) String() string {
+	if !p.ok {
+		return ""
+	}
+	return strconv.FormatFloat(p.value, 'f', 1, 64) + "%"
+}
+
+func (p percent) MarshalJSON() ([]byte, error) {
+	if !p.ok {
+		return []byte("null"), nil
+	}
+	return strconv.AppendFloat(nil, p.value, 'f', 1, 64), nil
+}
+
+// Adds a row of cells in the order of the columns.
+func (r *report) add(cells ...any) {
+	r.rows = append(r.rows, cells)
+}
+
+// Writes the report in the provided format.
+func (r report) write(w io.Writer, f reportFormat) error {
+	switch f {
+	case reportCsv:
+		return r.writeCsv(w)
+	case reportJson:
+		return r.writeJson(w)
+	default:
+		return r.writeTable(w)
+	}
+}
+
+func (r report) writeTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.ToUpper(strings.Join(r.columns, "\t")))
+	for _, row := range r.rows {
+		cells := make([]string, len(row))
+		for i, c := range row {
+			cells[i] = fmt.Sprint(c)
+		}
+		fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	}
+	return tw.Flush()
+}
+
+func (r report) writeCsv(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Write(r.columns)
+	for _, row := range r.rows {
+		cells := make([]string, len(row))
+		for i, c := range row {
+			cells[i] = fmt.Sprint(c)
+		}
+		cw.Write(cells)
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// Writes the rows as an array of objects, keeping the keys in column order.
+func (r report) writeJson(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString("[")
+	for i, row := range r.rows {
+		if i > 0 {
+			bw.WriteString(",")
+		}
+		bw.WriteString("\n  {")
+		for j, c := range row {
+			if j > 0 {
+				bw.WriteString(", ")
+			}
+			key, _ := json.Marshal(r.columns[j])
+			value, err := json.Marshal(c)
+			if err != nil {
+				return fmt.Errorf("report could not be written: %v", err)
+			}
+			fmt.Fprintf(bw, "%s: %s", key, value)
+		}
+		bw.WriteString("}")
+	}
+	if len(r.rows) > 0 {
+		bw.WriteString("\n")
+	}
+	bw.WriteString("]\n")
+	return bw.Flush()
+}
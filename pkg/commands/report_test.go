@@ -0,0 +1,54 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"statements/pkg/analysis"
+	"statements/pkg/transactions"
+)
+
+func TestReportWrite(t *testing.T) {
+	r := report{columns: []string{"month", "net", "count"}}
+	r.add("2025-01", amount(-1205), 2)
+
+	tests := []struct {
+		format reportFormat
+		want   string
+	}{
+		{reportTable, "MONTH    NET     COUNT\n2025-01  -12.05  2\n"},
+		{reportCsv, "month,net,count\n2025-01,-12.05,2\n"},
+		{reportJson, "[\n  {\"month\": \"2025-01\", \"net\": -12.05, \"count\": 2}\n]\n"},
+	}
+	for _, tt := range tests {
+		var b strings.Builder
+		if err := r.write(&b, tt.format); err != nil {
+			t.Fatalf("write(%s) unexpected error: %v", tt.format, err)
+		}
+		if b.String() != tt.want {
+			t.Errorf("write(%s) = %q, want %q", tt.format, b.String(), tt.want)
+		}
+	}
+}
+
+func TestReportWriteEmptyJson(t *testing.T) {
+	var b strings.Builder
+	if err := (report{columns: []string{"month"}}).write(&b, reportJson); err != nil {
+		t.Fatalf("write() unexpected error: %v", err)
+	}
+	if b.String() != "[]\n" {
+		t.Errorf("write() = %q, want an empty array", b.String())
+	}
+}
+
+func TestAnomaliesReport(t *testing.T) {
+	r := anomaliesReport([]analysis.Anomaly{{
+		Transaction: transactions.Transaction{Date: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), AccountHolder: "NETFLIX.COM 866-579", Payee: "Netflix", Value: -1799, Currency: "EUR"},
+		Kind:        analysis.AnomalyOutlier,
+		Reason:      "Netflix charged 17.99",
+	}})
+	if got := r.rows[0][1]; got != "Netflix" {
+		t.Errorf("anomaliesReport() counterparty = %v, want the payee used by the reason", got)
+	}
+}
@@ -0,0 +1,170 @@
+package commands
+
+import (
+	"bufio"
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"statements/pkg/config"
+	"statements/pkg/ledger"
+	"statements/pkg/review"
+	"statements/pkg/transactions"
+
+	"github.com/spf13/cobra"
+)
+
+func NewReviewCommand() *cobra.Command {
+	var history historyFlags
+	var sidefile, confile *string
+
+	cmd := &cobra.Command{
+		Use:   "review",
+		Short: "Review, categorize and tag transactions interactively",
+		Long: "Review transactions in an interactive terminal list: search and filter them, assign " +
+			"categories, tags and notes, mark them as reviewed and create category rules for their payees. " +
+			"Changes to ledger transactions are saved to the ledger, while changes to transactions of " +
+			"input files, which are never modified, are saved to a sidecar file. Other commands apply " +
+			"the sidecar file only when it is configured as review.sidecar. Created rules are always " +
+			"saved to the sidecar file. The interactive list needs a Linux terminal; on other " +
+			"platforms use the tag and split commands instead.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := config.Parse(*confile)
+			if err != nil {
+				return fmt.Errorf("could not parse config file: %v", err)
+			}
+			store, err := openReviewStore(cmd.ErrOrStderr(), history, &c, *sidefile)
+			if err != nil {
+				return err
+			}
+
+			seq, err := history.loadRows(cmd.ErrOrStderr(), c, false)
+			if err != nil {
+				return err
+			}
+			ts, err := transactions.Collect(seq)
+			if err != nil {
+				return err
+			}
+			store.track(ts)
+
+			return runReview(review.NewSession(ts, store.save), os.Stdin, os.Stdout)
+		},
+	}
+
+	history = addHistoryFlags(cmd)
+
+	sidefile = cmd.Flags().String("sidecar", "", fmt.Sprintf("file storing review edits of input files and created rules, defaulting to the configured file or %s", review.DefaultSidecar))
+	cmd.MarkFlagFilename("sidecar", "json")
+
+	confile = cmd.Flags().String("config", config.DefaultConfig, "configuration file to use")
+
+	return cmd
+}
+
+// Where edits are saved: the ledger for ledger transactions, and the sidecar file for input
+// files, which are never modified. Created rules are always saved to the sidecar file.
+type reviewStore struct {
+	ledger  *ledger.Ledger
+	sidecar *review.Sidecar
+	// The transactions as loaded or last saved, which the edits of the sidecar file are
+	// recorded against.
+	saved map[string]transactions.Transaction
+}
+
+// Opens the store of the transactions selected by the history flags.
+//
+// The sidecar file is resolved from `sidecar`, the configuration or the default, and set in the
+// configuration so loading input files applies the same file. As other commands only apply the
+// configured sidecar file, a warning is written to `w` if edits of input files go elsewhere.
+func openReviewStore(w io.Writer, history historyFlags, c *config.Config, sidecar string) (reviewStore, error) {
+	s := reviewStore{saved: map[string]transactions.Transaction{}}
+	configured := c.Review.Sidecar
+	c.Review.Sidecar = cmp.Or(sidecar, c.Review.Sidecar, review.DefaultSidecar)
+	if c.Review.Sidecar != configured && !history.usesLedger() {
+		fmt.Fprintf(w, "Warning: edits are saved to %s, which other commands only apply when configured as review.sidecar\n", c.Review.Sidecar)
+	}
+
+	sc, err := review.OpenSidecar(c.Review.Sidecar)
+	if err != nil {
+		return s, err
+	}
+	s.sidecar = sc
+	if history.usesLedger() {
+		if s.ledger, err = openLedger(*history.ledger, *c); err != nil {
+			return s, err
+		}
+	}
+	return s, nil
+}
+
+// Keeps the loaded transactions, so that only the fields changed afterwards are recorded.
+func (s reviewStore) track(ts []transactions.Transaction) {
+	for _, t := range ts {
+		s.saved[t.ID] = t
+	}
+}
+
+// Saves changed transactions and created rules.
+func (s reviewStore) save(changed []transactions.Transaction, rules []config.CategoryRule) error {
+	for _, t := range changed {
+		if s.ledger != nil {
+			s.ledger.Update(t)
+		} else {
+			s.sidecar.Record(s.saved[t.ID], t)
+			s.saved[t.ID] = t
+		}
+	}
+	for _, r := range rules {
+		s.sidecar.AddRule(r)
+	}
+	if s.ledger != nil && len(changed) > 0 {
+		if err := s.ledger.Save(); err != nil {
+			return err
+		}
+	}
+	if s.ledger == nil || len(rules) > 0 {
+		return s.sidecar.Save()
+	}
+	return nil
+}
+
+// Runs a review session on the terminal until it is done, drawing it on the alternate screen.
+func runReview(s *review.Session, in, out *os.File) error {
+	restore, err := review.MakeRaw(int(in.Fd()))
+	if err != nil {
+		return fmt.Errorf("review needs an interactive terminal: %v", err)
+	}
+	defer restore()
+
+	fmt.Fprint(out, "\x1b[?1049h\x1b[?25l")
+	defer fmt.Fprint(out, "\x1b[?25h\x1b[?1049l")
+
+	r := bufio.NewReader(in)
+	w := bufio.NewWriter(out)
+	for !s.Done() {
+		cols, rows, err := review.Size(int(out.Fd()))
+		if err != nil {
+			cols, rows = 80, 24
+		}
+		if err := s.Render(w, cols, rows); err != nil {
+			return err
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+
+		k, err := review.ReadKey(r)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		s.HandleKey(k)
+	}
+	return nil
+}
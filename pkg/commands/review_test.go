@@ -0,0 +1,40 @@
+package commands
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"statements/pkg/config"
+
+	"github.com/spf13/cobra"
+)
+
+func TestOpenReviewStore_UnconfiguredSidecar(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "edits.json")
+	tests := []struct {
+		name    string
+		config  string
+		sidecar string
+		warn    bool
+	}{
+		{name: "default file", warn: true},
+		{name: "passed file", sidecar: path, warn: true},
+		{name: "configured file", config: path},
+		{name: "configured file passed", config: path, sidecar: path},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			history := addHistoryFlags(&cobra.Command{Use: "test"})
+			c := config.Config{Review: config.ReviewConfig{Sidecar: tt.config}}
+			var w bytes.Buffer
+			if _, err := openReviewStore(&w, history, &c, tt.sidecar); err != nil {
+				t.Fatalf("openReviewStore() unexpected error: %v", err)
+			}
+			if got := strings.Contains(w.String(), "review.sidecar"); got != tt.warn {
+				t.Errorf("openReviewStore() warned %q, want a warning %t", w.String(), tt.warn)
+			}
+		})
+	}
+}
@@ -9,8 +9,21 @@ func NewRootCommand() *cobra.Command {
 		Long:  "Utility tool for automatically parsing and analyzing bank statements",
 	}
 
+	cmd.AddCommand(NewAnomaliesCommand())
+	cmd.AddCommand(NewBudgetCommand())
+	cmd.AddCommand(NewCategorizeCommand())
+	cmd.AddCommand(NewChartCommand())
+	cmd.AddCommand(NewCompareCommand())
 	cmd.AddCommand(NewConfigCommand())
+	cmd.AddCommand(NewForecastCommand())
+	cmd.AddCommand(NewImportCommand())
+	cmd.AddCommand(NewLedgerCommand())
 	cmd.AddCommand(NewProcessCommand())
+	cmd.AddCommand(NewRecurringCommand())
+	cmd.AddCommand(NewReviewCommand())
+	cmd.AddCommand(NewSplitCommand())
+	cmd.AddCommand(NewSummaryCommand())
+	cmd.AddCommand(NewTagCommand())
 	cmd.AddCommand(NewVersionCommand())
 
 	return cmd
@@ -0,0 +1,143 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"statements/pkg/config"
+	"statements/pkg/review"
+	"statements/pkg/transactions"
+
+	"github.com/spf13/cobra"
+)
+
+func NewSplitCommand() *cobra.Command {
+	var history historyFlags
+	var clearSplits *bool
+	var sidefile, confile *string
+
+	cmd := &cobra.Command{
+		Use:   "split id [category=amount[=note]]...",
+		Short: "Split a transaction across categories",
+		Long: "Split a transaction into lines with their own category, amount and note, such as the " +
+			"groceries and household goods on a single receipt, then list its lines. Amounts use a dot " +
+			"as the decimal separator and take the sign of the transaction unless signed, and a single " +
+			"split without an amount takes the remainder. The splits must add up to the transaction " +
+			"value. Reports use the split lines, while exports write the original transaction unless " +
+			"splits are requested. Like with the review, changes to ledger transactions are saved to " +
+			"the ledger, while changes to transactions of input files are saved to the sidecar file. " +
+			"Other commands apply the sidecar file only when it is configured as review.sidecar.",
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if *clearSplits && len(args) > 1 {
+				return errors.New("splits cannot be provided when clearing them")
+			}
+
+			c, err := config.Parse(*confile)
+			if err != nil {
+				return fmt.Errorf("could not parse config file: %v", err)
+			}
+			store, err := openReviewStore(cmd.ErrOrStderr(), history, &c, *sidefile)
+			if err != nil {
+				return err
+			}
+
+			seq, err := history.loadRows(cmd.ErrOrStderr(), c, false)
+			if err != nil {
+				return err
+			}
+			ts, err := transactions.Collect(seq)
+			if err != nil {
+				return err
+			}
+			store.track(ts)
+			i := slices.IndexFunc(ts, func(t transactions.Transaction) bool { return t.ID == args[0] })
+			if i < 0 {
+				return fmt.Errorf("transaction %s not found", args[0])
+			}
+
+			t := ts[i]
+			if len(args) > 1 || *clearSplits {
+				splits, err := parseSplits(args[1:], t.Value)
+				if err != nil {
+					return err
+				}
+				t.Splits = splits
+				if err := t.ValidateSplits(); err != nil {
+					return fmt.Errorf("transaction %s: %v", t.ID, err)
+				}
+				if !slices.Equal(t.Splits, ts[i].Splits) {
+					if err := store.save([]transactions.Transaction{t}, nil); err != nil {
+						return err
+					}
+				}
+			}
+
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "ID\tCATEGORY\tVALUE\tCURRENCY\tNOTES")
+			for _, l := range t.Lines() {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", l.ID, l.Category, transactions.FormatValue(l.Value, "."), l.Currency, l.Notes)
+			}
+			return w.Flush()
+		},
+	}
+
+	history = addHistoryFlags(cmd)
+
+	clearSplits = cmd.Flags().Bool("clear", false, "remove the splits of the transaction")
+
+	sidefile = cmd.Flags().String("sidecar", "", fmt.Sprintf("file storing edits of input files, defaulting to the configured file or %s", review.DefaultSidecar))
+	cmd.MarkFlagFilename("sidecar", "json")
+
+	confile = cmd.Flags().String("config", config.DefaultConfig, "configuration file to use")
+
+	return cmd
+}
+
+// Parses splits written as `category=amount[=note]` of a transaction with the provided value.
+//
+// Unsigned amounts take the sign of the transaction, and a single split without an amount takes
+// the remainder of the value.
+func parseSplits(specs []string, value int) ([]transactions.Split, error) {
+	if len(specs) == 0 {
+		return nil, nil
+	}
+
+	splits := make([]transactions.Split, len(specs))
+	remainder, rest := -1, value
+	for i, spec := range specs {
+		category, amount, ok := strings.Cut(spec, "=")
+		if !ok || strings.TrimSpace(category) == "" {
+			return nil, fmt.Errorf("invalid split %q: expected category=amount[=note]", spec)
+		}
+		amount, note, _ := strings.Cut(amount, "=")
+		splits[i] = transactions.Split{Category: strings.TrimSpace(category), Note: strings.TrimSpace(note)}
+
+		amount = strings.TrimSpace(amount)
+		if amount == "" {
+			if remainder >= 0 {
+				return nil, fmt.Errorf("invalid split %q: only one split can take the remainder", spec)
+			}
+			remainder = i
+			continue
+		}
+
+		unsigned, positive := strings.CutPrefix(amount, "+")
+		v, err := transactions.ParseValue(unsigned, ".", "")
+		if err != nil {
+			return nil, fmt.Errorf("invalid split %q: %v", spec, err)
+		}
+		if !positive && !strings.HasPrefix(amount, "-") && value < 0 {
+			v = -v
+		}
+		splits[i].Value = v
+		rest -= v
+	}
+	if remainder >= 0 {
+		splits[remainder].Value = rest
+	}
+	return splits, nil
+}
@@ -0,0 +1,62 @@
+package commands
+
+import (
+	"slices"
+	"testing"
+
+	"statements/pkg/transactions"
+)
+
+func TestParseSplits(t *testing.T) {
+	tests := []struct {
+		name  string
+		specs []string
+		value int
+		want  []transactions.Split
+	}{
+		{
+			name:  "unsigned amounts take the sign of a debit",
+			specs: []string{"Groceries=38.61", "Household=13=Detergent"},
+			value: -5161,
+			want:  []transactions.Split{{Category: "Groceries", Value: -3861}, {Category: "Household", Value: -1300, Note: "Detergent"}},
+		},
+		{
+			name:  "remainder",
+			specs: []string{"Groceries=", "Household=13.00"},
+			value: -5161,
+			want:  []transactions.Split{{Category: "Groceries", Value: -3861}, {Category: "Household", Value: -1300}},
+		},
+		{
+			name:  "signed amounts are kept",
+			specs: []string{"Salary=2600", "Fees=-100"},
+			value: 250000,
+			want:  []transactions.Split{{Category: "Salary", Value: 260000}, {Category: "Fees", Value: -10000}},
+		},
+		{
+			name:  "explicit positive amount of a debit",
+			specs: []string{"Shopping=", "Refunds=+5"},
+			value: -2000,
+			want:  []transactions.Split{{Category: "Shopping", Value: -2500}, {Category: "Refunds", Value: 500}},
+		},
+		{
+			name: "clearing",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseSplits(tt.specs, tt.value)
+			if err != nil {
+				t.Fatalf("parseSplits() unexpected error: %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("parseSplits() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	for _, specs := range [][]string{{"Groceries"}, {"=10"}, {"Groceries=", "Household="}, {"Groceries=1.005"}} {
+		if _, err := parseSplits(specs, -5161); err == nil {
+			t.Errorf("parseSplits(%q) expected an error", specs)
+		}
+	}
+}
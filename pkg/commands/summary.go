@@ -0,0 +1,67 @@
+package commands
+
+import (
+	"fmt"
+
+	"statements/pkg/analysis"
+	"statements/pkg/config"
+
+	"github.com/spf13/cobra"
+)
+
+func NewSummaryCommand() *cobra.Command {
+	var history historyFlags
+	var groupBy, format, confile *string
+
+	cmd := &cobra.Command{
+		Use:   "summary",
+		Short: "Summarize income and spending of bank statements",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := config.Parse(*confile)
+			if err != nil {
+				return fmt.Errorf("could not parse config file: %v", err)
+			}
+
+			g, err := analysis.ParseGrouping(*groupBy)
+			if err != nil {
+				return err
+			}
+			f, err := parseReportFormat(*format)
+			if err != nil {
+				return err
+			}
+
+			seq, err := history.load(cmd.ErrOrStderr(), c, false)
+			if err != nil {
+				return err
+			}
+
+			s := analysis.NewSummary(g)
+			if err := s.AddAll(seq); err != nil {
+				return err
+			}
+
+			return summaryReport(s).write(cmd.OutOrStdout(), f)
+		},
+	}
+
+	history = addHistoryFlags(cmd)
+
+	groupBy = cmd.Flags().StringP("group-by", "g", string(analysis.GroupMonth), fmt.Sprintf("dimension to group transactions by, one of %v", analysis.Groupings))
+
+	format = cmd.Flags().StringP("format", "f", string(reportTable), fmt.Sprintf("report format, one of %v", reportFormats))
+
+	confile = cmd.Flags().String("config", config.DefaultConfig, "configuration file to use")
+
+	return cmd
+}
+
+// Builds a report with a row per group and currency of the summary.
+func summaryReport(s *analysis.Summary) report {
+	r := report{columns: []string{string(s.By), "currency", "income", "spending", "net", "count"}}
+	for _, g := range s.Groups() {
+		r.add(g.Key, g.Currency, amount(g.Income), amount(g.Spending), amount(g.Net()), g.Count)
+	}
+	return r
+}
@@ -0,0 +1,112 @@
+package commands
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"statements/pkg/config"
+	"statements/pkg/ctime"
+	"statements/pkg/review"
+	"statements/pkg/transactions"
+
+	"github.com/spf13/cobra"
+)
+
+func NewTagCommand() *cobra.Command {
+	var history historyFlags
+	var add, remove *[]string
+	var notes, sidefile, confile *string
+
+	cmd := &cobra.Command{
+		Use:   "tag id...",
+		Short: "Tag or annotate transactions by ID",
+		Long: "Add or remove tags and set the notes of transactions by ID, then list their tags and " +
+			"notes. Tags are written with or without a leading #. Like with the review, changes to " +
+			"ledger transactions are saved to the ledger, while changes to transactions of input files " +
+			"are saved to the sidecar file. Other commands apply the sidecar file only when it is " +
+			"configured as review.sidecar.",
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := config.Parse(*confile)
+			if err != nil {
+				return fmt.Errorf("could not parse config file: %v", err)
+			}
+			store, err := openReviewStore(cmd.ErrOrStderr(), history, &c, *sidefile)
+			if err != nil {
+				return err
+			}
+
+			seq, err := history.loadRows(cmd.ErrOrStderr(), c, false)
+			if err != nil {
+				return err
+			}
+			ts, err := transactions.Collect(seq)
+			if err != nil {
+				return err
+			}
+			store.track(ts)
+
+			var selected, changed []transactions.Transaction
+			for _, id := range slices.Compact(slices.Sorted(slices.Values(args))) {
+				i := slices.IndexFunc(ts, func(t transactions.Transaction) bool { return t.ID == id })
+				if i < 0 {
+					return fmt.Errorf("transaction %s not found", id)
+				}
+
+				t := ts[i]
+				tags := transactions.AddTags(transactions.RemoveTags(t.Tags, *remove...), *add...)
+				note := t.Notes
+				if cmd.Flags().Changed("notes") {
+					note = strings.TrimSpace(*notes)
+				}
+				if !slices.Equal(tags, t.Tags) || note != t.Notes {
+					t.Tags, t.Notes = tags, note
+					changed = append(changed, t)
+				}
+				selected = append(selected, t)
+			}
+
+			if len(changed) > 0 {
+				if err := store.save(changed, nil); err != nil {
+					return err
+				}
+			}
+
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "ID\tDATE\tPAYEE\tVALUE\tCURRENCY\tTAGS\tNOTES")
+			for _, t := range selected {
+				payee := t.Payee
+				if payee == "" {
+					payee = t.AccountHolder
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+					t.ID,
+					t.Date.Format(ctime.LittleEndianDateOnly),
+					payee,
+					transactions.FormatValue(t.Value, "."),
+					t.Currency,
+					strings.Join(t.Tags, " "),
+					t.Notes,
+				)
+			}
+			return w.Flush()
+		},
+	}
+
+	history = addHistoryFlags(cmd)
+
+	add = cmd.Flags().StringSliceP("add", "a", nil, "tag to add, can be repeated")
+
+	remove = cmd.Flags().StringSliceP("remove", "r", nil, "tag to remove, can be repeated")
+
+	notes = cmd.Flags().StringP("notes", "n", "", "notes to set, replacing existing notes, or empty to clear them")
+
+	sidefile = cmd.Flags().String("sidecar", "", fmt.Sprintf("file storing edits of input files, defaulting to the configured file or %s", review.DefaultSidecar))
+	cmd.MarkFlagFilename("sidecar", "json")
+
+	confile = cmd.Flags().String("config", config.DefaultConfig, "configuration file to use")
+
+	return cmd
+}
@@ -0,0 +1,34 @@
+package config
+
+// Account names used by plain-text accounting output defined in the configuration file.
+type AccountingConfig struct {
+	// The asset account of the bank, used for statement accounts without their own mapping.
+	AssetAccount string `json:"assetAccount,omitempty"`
+	// Asset accounts by the statement account number.
+	AssetAccounts map[string]string `json:"assetAccounts,omitempty"`
+	// Accounts by transaction category.
+	Accounts map[string]string `json:"accounts,omitempty"`
+	// The account for uncategorized credits.
+	IncomeAccount string `json:"incomeAccount,omitempty"`
+	// The account for uncategorized debits.
+	ExpenseAccount string `json:"expenseAccount,omitempty"`
+	// The account the first opening balance of each asset account is posted against.
+	OpeningBalanceAccount string `json:"openingBalanceAccount,omitempty"`
+}
+
+// Returns the accounting configuration with defaults applied to unset options.
+func (a AccountingConfig) WithDefaults() AccountingConfig {
+	if a.AssetAccount == "" {
+		a.AssetAccount = "Assets:Bank"
+	}
+	if a.IncomeAccount == "" {
+		a.IncomeAccount = "Income:Uncategorized"
+	}
+	if a.ExpenseAccount == "" {
+		a.ExpenseAccount = "Expenses:Uncategorized"
+	}
+	if a.OpeningBalanceAccount == "" {
+		a.OpeningBalanceAccount = "Equity:Opening-Balances"
+	}
+	return a
+}
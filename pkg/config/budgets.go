@@ -0,0 +1,26 @@
+package config
+
+type BudgetPeriod string
+
+const (
+	BudgetMonthly BudgetPeriod = "monthly"
+	BudgetYearly  BudgetPeriod = "yearly"
+)
+
+// A spending limit for the transactions of a category or matching a set of filters.
+//
+// If both a category and filters are provided, transactions must match both.
+type Budget struct {
+	// The name the budget is reported as, defaulting to its category.
+	Name     string       `json:"name,omitempty"`
+	Category string       `json:"category,omitempty"`
+	Filters  []RawFilter  `json:"filters,omitempty"`
+	Period   BudgetPeriod `json:"period,omitempty"`
+	// The limit per period in major units.
+	Limit float64 `json:"limit"`
+	// The currency of the limit, defaulting to the currency of the counted transactions if they
+	// share one.
+	Currency string `json:"currency,omitempty"`
+	// Whether the unspent or overspent amount of past periods is added to the limit.
+	CarryOver bool `json:"carryOver,omitempty"`
+}
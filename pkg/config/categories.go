@@ -0,0 +1,7 @@
+package config
+
+// A rule assigning a category to normalized transactions that match all of its filters.
+type CategoryRule struct {
+	Category string      `json:"category"`
+	Filters  []RawFilter `json:"filters"`
+}
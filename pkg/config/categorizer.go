@@ -0,0 +1,10 @@
+package config
+
+// Options of the categorizer trained on labeled transactions.
+type CategorizerConfig struct {
+	// The model file written by the `categorize train` command. If set, transactions that no
+	// category rule matches are categorized by the model while processing.
+	Model string `json:"model,omitempty"`
+	// The confidence between 0 and 1 from which suggestions of the model are applied.
+	Threshold float64 `json:"threshold,omitempty"`
+}
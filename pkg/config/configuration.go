@@ -9,8 +9,19 @@ import (
 )
 
 type Config struct {
-	Flags   FlagConfig  `json:"flags"`
-	Filters []RawFilter `json:"filters"`
+	Flags       FlagConfig        `json:"flags"`
+	Filters     []RawFilter       `json:"filters"`
+	Extract     []ExtractRule     `json:"extract"`
+	Payees      []PayeeRule       `json:"payees"`
+	Categories  []CategoryRule    `json:"categories"`
+	Tags        []TagRule         `json:"tags"`
+	Transfers   TransferConfig    `json:"transfers"`
+	Categorizer CategorizerConfig `json:"categorizer"`
+	Output      OutputConfig      `json:"output"`
+	Accounting  AccountingConfig  `json:"accounting"`
+	Budgets     []Budget          `json:"budgets"`
+	Forecast    ForecastConfig    `json:"forecast"`
+	Review      ReviewConfig      `json:"review"`
 }
 
 const DefaultConfig = "config.json"
@@ -53,6 +53,183 @@ func TestValidate(t *testing.T) {
 			}`,
 			wantErr: false,
 		},
+		{
+			name:   "valid config with budgets",
+			config: "test_budgets_config.json",
+			content: `{
+				"flags": {
+					"bank": "swedbank"
+				},
+				"budgets": [
+					{"category": "Groceries", "limit": 400, "carryOver": true},
+					{"name": "Large", "filters": [{"field": "value", "condition": "LESS_THAN", "comparison": -100}], "limit": 1000, "period": "yearly"}
+				]
+			}`,
+			wantErr: false,
+		},
+		{
+			name:   "invalid config - budget without category or filters",
+			config: "test_invalid_budget_config.json",
+			content: `{
+				"flags": {
+					"bank": "swedbank"
+				},
+				"budgets": [
+					{"limit": 400}
+				]
+			}`,
+			wantErr:    true,
+			errContain: "invalid",
+		},
+		{
+			name:   "valid config with payees",
+			config: "test_payees_config.json",
+			content: `{
+				"flags": {
+					"bank": "swedbank"
+				},
+				"payees": [
+					{"payee": "Maxima", "match": "^maxima\\b"},
+					{"payee": "Netflix", "contains": "netflix"}
+				],
+				"categories": [
+					{"category": "Groceries", "filters": [{"field": "payee", "condition": "EQUAL", "comparison": "Maxima"}]}
+				]
+			}`,
+			wantErr: false,
+		},
+		{
+			name:   "valid config with extraction rules",
+			config: "test_extract_config.json",
+			content: `{
+				"flags": {
+					"bank": "swedbank"
+				},
+				"extract": [
+					{"pattern": "^PIRKUMS (?P<card>\\d+) (?P<merchant>.+)$", "rewrite": "$merchant"},
+					{"field": "reference", "pattern": "^INV-(?P<invoice>\\d+)$"}
+				],
+				"categories": [
+					{"category": "Invoices", "filters": [{"field": "metadata.invoice", "condition": "NOT_EQUAL", "comparison": ""}]}
+				]
+			}`,
+			wantErr: false,
+		},
+		{
+			name:   "invalid config - extraction rule on a number field",
+			config: "test_invalid_extract_config.json",
+			content: `{
+				"flags": {
+					"bank": "swedbank"
+				},
+				"extract": [
+					{"field": "value", "pattern": "1"}
+				]
+			}`,
+			wantErr:    true,
+			errContain: "invalid",
+		},
+		{
+			name:   "valid config with categorizer",
+			config: "test_categorizer_config.json",
+			content: `{
+				"flags": {
+					"bank": "swedbank"
+				},
+				"categorizer": {"model": "model.json", "threshold": 0.8}
+			}`,
+			wantErr: false,
+		},
+		{
+			name:   "invalid config - categorizer threshold above one",
+			config: "test_invalid_categorizer_config.json",
+			content: `{
+				"flags": {
+					"bank": "swedbank"
+				},
+				"categorizer": {"model": "model.json", "threshold": 90}
+			}`,
+			wantErr:    true,
+			errContain: "invalid",
+		},
+		{
+			name:   "valid config with review sidecar",
+			config: "test_review_config.json",
+			content: `{
+				"flags": {
+					"bank": "swedbank"
+				},
+				"review": {"sidecar": "edits.json"}
+			}`,
+			wantErr: false,
+		},
+		{
+			name:   "valid config with tag rules",
+			config: "test_tags_config.json",
+			content: `{
+				"flags": {
+					"bank": "swedbank"
+				},
+				"tags": [
+					{"tags": ["#reimbursable", "work"], "filters": [{"field": "tags", "condition": "NOT_EQUAL", "comparison": "private"}]}
+				]
+			}`,
+			wantErr: false,
+		},
+		{
+			name:   "invalid config - tag with spaces",
+			config: "test_invalid_tags_config.json",
+			content: `{
+				"flags": {
+					"bank": "swedbank"
+				},
+				"tags": [
+					{"tags": ["tax deductible"], "filters": []}
+				]
+			}`,
+			wantErr:    true,
+			errContain: "invalid",
+		},
+		{
+			name:   "valid config with transfers",
+			config: "test_transfers_config.json",
+			content: `{
+				"flags": {
+					"bank": "swedbank"
+				},
+				"transfers": {"accounts": ["LV01HABA0000000000001", "LV02 HABA 0000 0000 0000 2"], "window": 5},
+				"categories": [
+					{"category": "Savings", "filters": [{"field": "transfer", "condition": "NOT_EQUAL", "comparison": ""}]}
+				]
+			}`,
+			wantErr: false,
+		},
+		{
+			name:   "invalid config - transfer window of zero days",
+			config: "test_invalid_transfers_config.json",
+			content: `{
+				"flags": {
+					"bank": "swedbank"
+				},
+				"transfers": {"accounts": ["LV01HABA0000000000001"], "window": 0}
+			}`,
+			wantErr:    true,
+			errContain: "invalid",
+		},
+		{
+			name:   "invalid config - payee rule without pattern",
+			config: "test_invalid_payee_config.json",
+			content: `{
+				"flags": {
+					"bank": "swedbank"
+				},
+				"payees": [
+					{"payee": "Maxima"}
+				]
+			}`,
+			wantErr:    true,
+			errContain: "invalid",
+		},
 		{
 			name:       "invalid config - missing required fields",
 			config:     "test_invalid_config.json",
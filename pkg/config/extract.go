@@ -0,0 +1,13 @@
+package config
+
+// A rule extracting named groups of a regular expression into metadata fields of normalized
+// transactions, optionally rewriting their description.
+type ExtractRule struct {
+	// The string field the pattern is matched against, defaulting to the description.
+	Field string `json:"field,omitempty"`
+	// The regular expression, whose named groups are stored as metadata fields.
+	Pattern string `json:"pattern"`
+	// The template replacing the description if the pattern matches, referring to groups as
+	// `$name` or `${name}`.
+	Rewrite string `json:"rewrite,omitempty"`
+}
@@ -28,8 +28,23 @@ func (r *RawFilter) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// Marshals the raw filter back into the JSON data it was decoded from.
+func (r RawFilter) MarshalJSON() ([]byte, error) {
+	if r.Raw == nil {
+		return []byte("null"), nil
+	}
+	return r.Raw, nil
+}
+
 // Decodes the raw filter into a typesafe filter based on a field map.
 func (r RawFilter) DecodeWithFieldMap(fields FieldMap) (Filter, error) {
+	return r.DecodeWithFieldTypes(func(field string) FieldType {
+		return fields[field]
+	})
+}
+
+// Decodes the raw filter into a typesafe filter, resolving the field type with a function.
+func (r RawFilter) DecodeWithFieldTypes(types func(field string) FieldType) (Filter, error) {
 	var meta struct {
 		Field string `json:"field"`
 	}
@@ -37,7 +52,7 @@ func (r RawFilter) DecodeWithFieldMap(fields FieldMap) (Filter, error) {
 		return nil, err
 	}
 
-	ftype := fields[meta.Field]
+	ftype := types(meta.Field)
 
 	switch ftype {
 	case FieldTypeDate:
@@ -219,12 +234,25 @@ func (f StringFilter) FieldName() string {
 }
 
 // Checks if a string filter matches a given value.
+//
+// A list of strings, such as tags, matches an equal or contain condition if any of its strings
+// does, and a negated condition if all of its strings do.
 func (f StringFilter) Match(value any) bool {
 	v := reflect.ValueOf(value)
 	if !v.IsValid() {
 		return false
 	}
 
+	if v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.String {
+		negated := f.Condition == StringNotEqual || f.Condition == StringNotContain
+		for i := range v.Len() {
+			if f.Match(v.Index(i).Interface()) != negated {
+				return !negated
+			}
+		}
+		return negated
+	}
+
 	var s string
 	switch v.Kind() {
 	case reflect.String:
@@ -432,6 +432,46 @@ func TestStringFilter_Match(t *testing.T) {
 			value:     123,
 			wantMatch: false,
 		},
+		{
+			name: "equal - list match",
+			filter: config.StringFilter{
+				Field:      "tags",
+				Condition:  config.StringEqual,
+				Comparison: "reimbursable",
+			},
+			value:     []string{"vacation-2025", "reimbursable"},
+			wantMatch: true,
+		},
+		{
+			name: "equal - empty list",
+			filter: config.StringFilter{
+				Field:      "tags",
+				Condition:  config.StringEqual,
+				Comparison: "reimbursable",
+			},
+			value:     []string(nil),
+			wantMatch: false,
+		},
+		{
+			name: "not equal - list match",
+			filter: config.StringFilter{
+				Field:      "tags",
+				Condition:  config.StringNotEqual,
+				Comparison: "reimbursable",
+			},
+			value:     []string{"vacation-2025"},
+			wantMatch: true,
+		},
+		{
+			name: "not contain - list no match",
+			filter: config.StringFilter{
+				Field:      "tags",
+				Condition:  config.StringNotContain,
+				Comparison: "vacation",
+			},
+			value:     []string{"work", "vacation-2025"},
+			wantMatch: false,
+		},
 	}
 
 	for _, tt := range tests {
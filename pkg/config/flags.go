@@ -5,4 +5,6 @@ type FlagConfig struct {
 	Bank   string `json:"bank,omitempty"`
 	Input  string `json:"input,omitempty"`
 	Output string `json:"output,omitempty"`
+	Format string `json:"format,omitempty"`
+	Ledger string `json:"ledger,omitempty"`
 }
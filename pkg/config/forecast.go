@@ -0,0 +1,7 @@
+package config
+
+// Options of cash-flow forecasts defined in the configuration file.
+type ForecastConfig struct {
+	// The balance in major units below which a projected balance is warned about.
+	Threshold float64 `json:"threshold,omitempty"`
+}
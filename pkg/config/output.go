@@ -0,0 +1,82 @@
+package config
+
+import "strings"
+
+type QuoteStyle string
+
+const (
+	// Quotes fields only when they contain the delimiter, quotes or line breaks.
+	QuoteMinimal QuoteStyle = "minimal"
+	// Quotes every field.
+	QuoteAll QuoteStyle = "all"
+	// Never quotes fields.
+	QuoteNone QuoteStyle = "none"
+)
+
+// Formatting of tabular output defined in the configuration file.
+type OutputConfig struct {
+	// The transaction fields to write, in order.
+	Columns []string `json:"columns,omitempty"`
+	// Whether to write a header row with the column names.
+	Header    bool       `json:"header,omitempty"`
+	Delimiter string     `json:"delimiter,omitempty"`
+	Quote     QuoteStyle `json:"quote,omitempty"`
+	// The date layout using the `YYYY`, `YY`, `MM` and `DD` placeholders.
+	DateLayout string `json:"dateLayout,omitempty"`
+	// The decimal separator of amounts.
+	Decimal string `json:"decimal,omitempty"`
+	// The thousands separator of amounts, not grouping digits if empty.
+	Thousands string `json:"thousands,omitempty"`
+	// Whether to start the output with a UTF-8 byte order mark.
+	Bom bool `json:"bom,omitempty"`
+	// The summaries added to spreadsheets and HTML reports, grouping transactions by a period such
+	// as `month`, or by `counterparty` or `category`.
+	Summaries []string `json:"summaries,omitempty"`
+	// Whether to write the lines of split transactions instead of the original transactions.
+	Splits bool `json:"splits,omitempty"`
+}
+
+// The columns written if none are configured.
+var DefaultColumns = []string{
+	"date",
+	"accountHolder",
+	"description",
+	"value",
+	"currency",
+	"id",
+	"kind",
+	"account",
+	"valueDate",
+	"counterpartyAccount",
+	"reference",
+	"bankReference",
+	"documentNumber",
+	"bankCode",
+	"metadata",
+}
+
+// Returns the output configuration with defaults applied to unset options.
+func (o OutputConfig) WithDefaults() OutputConfig {
+	if len(o.Columns) == 0 {
+		o.Columns = DefaultColumns
+	}
+	if o.Delimiter == "" {
+		o.Delimiter = ";"
+	}
+	if o.Quote == "" {
+		o.Quote = QuoteMinimal
+	}
+	if o.DateLayout == "" {
+		o.DateLayout = "DD.MM.YYYY"
+	}
+	if o.Decimal == "" {
+		o.Decimal = ","
+	}
+	return o
+}
+
+// Converts the configured date layout into a Go time layout.
+func (o OutputConfig) TimeLayout() string {
+	r := strings.NewReplacer("YYYY", "2006", "YY", "06", "MM", "01", "DD", "02")
+	return r.Replace(o.DateLayout)
+}
@@ -0,0 +1,12 @@
+package config
+
+// A rule mapping the counterparty of normalized transactions to a canonical payee.
+//
+// Rules match the counterparty name or the description case-insensitively, by a regular
+// expression, a substring or both.
+type PayeeRule struct {
+	// The canonical payee to assign.
+	Payee    string `json:"payee"`
+	Match    string `json:"match,omitempty"`
+	Contains string `json:"contains,omitempty"`
+}
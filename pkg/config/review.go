@@ -0,0 +1,9 @@
+package config
+
+// Options of the interactive review.
+type ReviewConfig struct {
+	// The file storing edits of reviewed input files and the category rules created while
+	// reviewing. Commands reading input files only apply it when configured, while the review
+	// commands default to `review.json`.
+	Sidecar string `json:"sidecar,omitempty"`
+}
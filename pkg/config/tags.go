@@ -0,0 +1,7 @@
+package config
+
+// A rule adding tags to normalized transactions that match all of its filters.
+type TagRule struct {
+	Tags    []string    `json:"tags"`
+	Filters []RawFilter `json:"filters"`
+}
@@ -0,0 +1,9 @@
+package config
+
+// Options of matching transfers between own accounts.
+type TransferConfig struct {
+	// The numbers or IBANs of own accounts. Transfers are only matched if any is configured.
+	Accounts []string `json:"accounts,omitempty"`
+	// The number of days the two sides of a transfer can be booked apart.
+	Window int `json:"window,omitempty"`
+}
@@ -0,0 +1,176 @@
+package ledger
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"statements/pkg/transactions"
+	"time"
+)
+
+const DefaultFile = "ledger.jsonl"
+
+// A persistent store of normalized transactions, kept as a single JSON lines file.
+type Ledger struct {
+	path string
+	ts   []transactions.Transaction
+	ids  map[string]bool
+}
+
+// Resolves the default ledger location within the user's data directory.
+//
+// Follows the XDG base directory specification, falling back to `~/.local/share`.
+func DefaultPath() (string, error) {
+	dir := os.Getenv("XDG_DATA_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("could not resolve data directory: %v", err)
+		}
+		dir = filepath.Join(home, ".local", "share")
+	}
+	return filepath.Join(dir, "statements", DefaultFile), nil
+}
+
+// Opens the ledger stored at the provided path.
+//
+// A ledger that does not exist yet is treated as empty and created on the first save.
+func Open(path string) (*Ledger, error) {
+	l := &Ledger{path: path, ids: map[string]bool{}}
+
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return l, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ledger could not be opened: %v", err)
+	}
+	defer f.Close()
+
+	s := bufio.NewScanner(f)
+	s.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for n := 1; s.Scan(); n++ {
+		if len(s.Bytes()) == 0 {
+			continue
+		}
+		var t transactions.Transaction
+		if err := json.Unmarshal(s.Bytes(), &t); err != nil {
+			return nil, fmt.Errorf("ledger entry %d could not be parsed: %v", n, err)
+		}
+		l.ts = append(l.ts, t)
+		l.ids[t.ID] = true
+	}
+	if err := s.Err(); err != nil {
+		return nil, fmt.Errorf("ledger could not be read: %v", err)
+	}
+
+	return l, nil
+}
+
+// The location of the ledger file.
+func (l *Ledger) Path() string {
+	return l.path
+}
+
+// All stored transactions, ordered by date.
+func (l *Ledger) Transactions() []transactions.Transaction {
+	return l.ts
+}
+
+// Adds transactions to the ledger, skipping the ones whose ID is already stored.
+//
+// Returns the added and skipped transactions. Changes are only persisted by `Save`.
+func (l *Ledger) Add(ts []transactions.Transaction) (added, skipped []transactions.Transaction) {
+	for _, t := range ts {
+		if t.ID == "" {
+			t.ID = t.DeriveID()
+		}
+		if l.ids[t.ID] {
+			skipped = append(skipped, t)
+			continue
+		}
+		l.ids[t.ID] = true
+		l.ts = append(l.ts, t)
+		added = append(added, t)
+	}
+
+	slices.SortStableFunc(l.ts, func(a, b transactions.Transaction) int {
+		return a.Date.Compare(b.Date)
+	})
+
+	return added, skipped
+}
+
+// Replaces the stored transaction with the same ID, such as after changing its category.
+//
+// Reports whether a transaction with the ID is stored. Changes are only persisted by `Save`.
+func (l *Ledger) Update(t transactions.Transaction) bool {
+	i := slices.IndexFunc(l.ts, func(s transactions.Transaction) bool { return s.ID == t.ID })
+	if i < 0 {
+		return false
+	}
+	l.ts[i] = t
+	return true
+}
+
+// Returns the stored transactions matching the account and date range.
+//
+// An empty account matches all accounts, and zero dates leave the range open.
+func (l *Ledger) Query(account string, from, to time.Time) []transactions.Transaction {
+	var res []transactions.Transaction
+	for _, t := range l.ts {
+		if account != "" && t.Account != account {
+			continue
+		}
+		if !from.IsZero() && t.Date.Before(from) {
+			continue
+		}
+		if !to.IsZero() && t.Date.After(to) {
+			continue
+		}
+		res = append(res, t)
+	}
+	return res
+}
+
+// Writes the ledger to disk.
+//
+// The file is replaced atomically, so an interrupted save never corrupts the stored data.
+func (l *Ledger) Save() error {
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o755); err != nil {
+		return fmt.Errorf("ledger directory could not be created: %v", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(l.path), filepath.Base(l.path)+".*")
+	if err != nil {
+		return fmt.Errorf("ledger could not be written: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	w := bufio.NewWriter(tmp)
+	enc := json.NewEncoder(w)
+	for _, t := range l.ts {
+		if err := enc.Encode(t); err != nil {
+			tmp.Close()
+			return fmt.Errorf("ledger could not be written: %v", err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("ledger could not be written: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("ledger could not be written: %v", err)
+	}
+
+	if err := os.Rename(tmp.Name(), l.path); err != nil {
+		return fmt.Errorf("ledger could not be written: %v", err)
+	}
+
+	return nil
+}
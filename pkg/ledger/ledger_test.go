@@ -0,0 +1,158 @@
+package ledger_test
+
+import (
+	"path/filepath"
+	"statements/pkg/ledger"
+	"statements/pkg/transactions"
+	"testing"
+	"time"
+)
+
+func sampleTransactions() []transactions.Transaction {
+	return []transactions.Transaction{
+		{
+			ID:       "b",
+			Kind:     transactions.KindTransaction,
+			Account:  "LV02HABA0123456789012",
+			Date:     time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+			Value:    -500,
+			Currency: "EUR",
+		},
+		{
+			ID:       "a",
+			Kind:     transactions.KindTransaction,
+			Account:  "LV02HABA0123456789012",
+			Date:     time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			Value:    1000,
+			Currency: "EUR",
+		},
+		{
+			ID:       "c",
+			Kind:     transactions.KindTransaction,
+			Account:  "LV97HABA0000000000001",
+			Date:     time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+			Value:    -200,
+			Currency: "EUR",
+		},
+	}
+}
+
+func TestOpen_Missing(t *testing.T) {
+	l, err := ledger.Open(filepath.Join(t.TempDir(), "missing.jsonl"))
+	if err != nil {
+		t.Fatalf("Open() unexpected error: %v", err)
+	}
+	if len(l.Transactions()) != 0 {
+		t.Errorf("Open() returned %d transactions, want 0", len(l.Transactions()))
+	}
+}
+
+func TestLedger_AddSaveReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data", ledger.DefaultFile)
+
+	l, err := ledger.Open(path)
+	if err != nil {
+		t.Fatalf("Open() unexpected error: %v", err)
+	}
+
+	added, skipped := l.Add(sampleTransactions())
+	if len(added) != 3 || len(skipped) != 0 {
+		t.Fatalf("Add() = %d added, %d skipped, want 3 and 0", len(added), len(skipped))
+	}
+	if err := l.Save(); err != nil {
+		t.Fatalf("Save() unexpected error: %v", err)
+	}
+
+	l, err = ledger.Open(path)
+	if err != nil {
+		t.Fatalf("Open() unexpected error: %v", err)
+	}
+	ts := l.Transactions()
+	if len(ts) != 3 {
+		t.Fatalf("reopened ledger has %d transactions, want 3", len(ts))
+	}
+	if ts[0].ID != "a" || ts[1].ID != "b" || ts[2].ID != "c" {
+		t.Errorf("ledger is not ordered by date: %s, %s, %s", ts[0].ID, ts[1].ID, ts[2].ID)
+	}
+
+	added, skipped = l.Add(sampleTransactions())
+	if len(added) != 0 || len(skipped) != 3 {
+		t.Errorf("re-Add() = %d added, %d skipped, want 0 and 3", len(added), len(skipped))
+	}
+}
+
+func TestLedger_Update(t *testing.T) {
+	l, err := ledger.Open(filepath.Join(t.TempDir(), ledger.DefaultFile))
+	if err != nil {
+		t.Fatalf("Open() unexpected error: %v", err)
+	}
+	l.Add(sampleTransactions())
+
+	tr := l.Transactions()[1]
+	tr.Category = "Groceries"
+	if !l.Update(tr) {
+		t.Fatal("Update() = false, want the stored transaction replaced")
+	}
+	if got := l.Transactions()[1].Category; got != "Groceries" {
+		t.Errorf("updated category = %q, want Groceries", got)
+	}
+
+	tr.ID = "missing"
+	if l.Update(tr) {
+		t.Error("Update() = true for a transaction that is not stored")
+	}
+}
+
+func TestLedger_Query(t *testing.T) {
+	l, err := ledger.Open(filepath.Join(t.TempDir(), ledger.DefaultFile))
+	if err != nil {
+		t.Fatalf("Open() unexpected error: %v", err)
+	}
+	l.Add(sampleTransactions())
+
+	tests := []struct {
+		name    string
+		account string
+		from    time.Time
+		to      time.Time
+		want    int
+	}{
+		{"all", "", time.Time{}, time.Time{}, 3},
+		{"account", "LV02HABA0123456789012", time.Time{}, time.Time{}, 2},
+		{"from", "", time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC), time.Time{}, 2},
+		{"to", "", time.Time{}, time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC), 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := l.Query(tt.account, tt.from, tt.to); len(got) != tt.want {
+				t.Errorf("Query() returned %d transactions, want %d", len(got), tt.want)
+			}
+		})
+	}
+}
+
+func TestStats(t *testing.T) {
+	ts := append(sampleTransactions(), transactions.Transaction{
+		Kind:     transactions.KindClosingBalance,
+		Account:  "LV02HABA0123456789012",
+		Date:     time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC),
+		Value:    500,
+		Currency: "EUR",
+	})
+
+	stats := ledger.Stats(ts)
+	if len(stats) != 2 {
+		t.Fatalf("Stats() returned %d groups, want 2", len(stats))
+	}
+
+	s := stats[0]
+	if s.Account != "LV02HABA0123456789012" {
+		t.Errorf("Stats()[0].Account = %q, want LV02HABA0123456789012", s.Account)
+	}
+	if s.Inflow != 1000 || s.Outflow != -500 {
+		t.Errorf("Stats()[0] flows = %d/%d, want 1000/-500", s.Inflow, s.Outflow)
+	}
+	if !s.From.Equal(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Stats()[0].From = %v, want 2025-01-01", s.From)
+	}
+}
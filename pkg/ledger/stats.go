@@ -0,0 +1,70 @@
+package ledger
+
+import (
+	"slices"
+	"statements/pkg/transactions"
+	"strings"
+	"time"
+)
+
+// Aggregated figures for the transactions of a single account and currency.
+type AccountStats struct {
+	Account  string
+	Currency string
+	Count    int
+	From     time.Time
+	To       time.Time
+	// The sum of all credits in minor units.
+	Inflow int
+	// The sum of all debits in minor units, as a negative number.
+	Outflow int
+}
+
+// Aggregates transactions by account and currency, ordered by account.
+//
+// Statement rows that do not move money, such as balances, are not included in the flows.
+func Stats(ts []transactions.Transaction) []AccountStats {
+	idx := map[[2]string]int{}
+	var stats []AccountStats
+
+	for _, t := range ts {
+		key := [2]string{t.Account, t.Currency}
+		i, ok := idx[key]
+		if !ok {
+			i = len(stats)
+			idx[key] = i
+			stats = append(stats, AccountStats{
+				Account:  t.Account,
+				Currency: t.Currency,
+				From:     t.Date,
+				To:       t.Date,
+			})
+		}
+
+		s := &stats[i]
+		s.Count++
+		if t.Date.Before(s.From) {
+			s.From = t.Date
+		}
+		if t.Date.After(s.To) {
+			s.To = t.Date
+		}
+		if !t.IsMovement() {
+			continue
+		}
+		if t.Value < 0 {
+			s.Outflow += t.Value
+		} else {
+			s.Inflow += t.Value
+		}
+	}
+
+	slices.SortFunc(stats, func(a, b AccountStats) int {
+		if c := strings.Compare(a.Account, b.Account); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Currency, b.Currency)
+	})
+
+	return stats
+}
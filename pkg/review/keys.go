@@ -0,0 +1,101 @@
+package review
+
+import (
+	"bufio"
+	"unicode"
+)
+
+// The keys the review distinguishes, besides printable characters.
+type KeyCode int
+
+const (
+	KeyRune KeyCode = iota
+	KeyUp
+	KeyDown
+	KeyPageUp
+	KeyPageDown
+	KeyHome
+	KeyEnd
+	KeyEnter
+	KeyBackspace
+	KeyEscape
+	KeyInterrupt
+	// A control key or escape sequence the review does not use.
+	KeyUnknown
+)
+
+// A key pressed on the terminal, holding the character of printable keys.
+type Key struct {
+	Code KeyCode
+	Rune rune
+}
+
+// The escape sequences of special keys, following the introducing `ESC [`.
+var sequences = map[string]KeyCode{
+	"A":  KeyUp,
+	"B":  KeyDown,
+	"H":  KeyHome,
+	"F":  KeyEnd,
+	"1~": KeyHome,
+	"4~": KeyEnd,
+	"5~": KeyPageUp,
+	"6~": KeyPageDown,
+	"7~": KeyHome,
+	"8~": KeyEnd,
+}
+
+// Reads a key from a terminal in raw mode.
+//
+// An escape character is read as the escape key unless the rest of a sequence is already
+// buffered, as terminals send the bytes of a sequence at once.
+func ReadKey(r *bufio.Reader) (Key, error) {
+	c, _, err := r.ReadRune()
+	if err != nil {
+		return Key{}, err
+	}
+
+	switch c {
+	case '\r', '\n':
+		return Key{Code: KeyEnter}, nil
+	case 127, '\b':
+		return Key{Code: KeyBackspace}, nil
+	case 3:
+		return Key{Code: KeyInterrupt}, nil
+	case 27:
+		if r.Buffered() == 0 {
+			return Key{Code: KeyEscape}, nil
+		}
+		return readSequence(r)
+	}
+	if unicode.IsPrint(c) {
+		return Key{Code: KeyRune, Rune: c}, nil
+	}
+	return Key{Code: KeyUnknown}, nil
+}
+
+// Reads the rest of an escape sequence, up to its final letter or tilde.
+func readSequence(r *bufio.Reader) (Key, error) {
+	intro, err := r.ReadByte()
+	if err != nil {
+		return Key{}, err
+	}
+	if intro != '[' && intro != 'O' {
+		return Key{Code: KeyUnknown}, nil
+	}
+
+	var seq []byte
+	for r.Buffered() > 0 {
+		b, err := r.ReadByte()
+		if err != nil {
+			return Key{}, err
+		}
+		seq = append(seq, b)
+		if b == '~' || b >= 'A' && b <= 'Z' || b >= 'a' && b <= 'z' {
+			break
+		}
+	}
+	if code, ok := sequences[string(seq)]; ok {
+		return Key{Code: code}, nil
+	}
+	return Key{Code: KeyUnknown}, nil
+}
@@ -0,0 +1,39 @@
+package review_test
+
+import (
+	"bufio"
+	"statements/pkg/review"
+	"strings"
+	"testing"
+)
+
+func TestReadKey(t *testing.T) {
+	r := bufio.NewReader(strings.NewReader("aä\r\x7f\x03\x1b[A\x1b[B\x1bOH\x1b[6~\x1b[24~\x01\x1b"))
+	want := []review.Key{
+		{Code: review.KeyRune, Rune: 'a'},
+		{Code: review.KeyRune, Rune: 'ä'},
+		{Code: review.KeyEnter},
+		{Code: review.KeyBackspace},
+		{Code: review.KeyInterrupt},
+		{Code: review.KeyUp},
+		{Code: review.KeyDown},
+		{Code: review.KeyHome},
+		{Code: review.KeyPageDown},
+		{Code: review.KeyUnknown},
+		{Code: review.KeyUnknown},
+		{Code: review.KeyEscape},
+	}
+
+	for i, w := range want {
+		got, err := review.ReadKey(r)
+		if err != nil {
+			t.Fatalf("ReadKey() #%d unexpected error: %v", i, err)
+		}
+		if got != w {
+			t.Errorf("ReadKey() #%d = %+v, want %+v", i, got, w)
+		}
+	}
+	if _, err := review.ReadKey(r); err == nil {
+		t.Error("ReadKey() expected an error at the end of the input")
+	}
+}
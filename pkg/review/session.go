@@ -0,0 +1,457 @@
+package review
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"statements/pkg/config"
+	"statements/pkg/ctime"
+	"statements/pkg/transactions"
+	"strings"
+	"unicode/utf8"
+)
+
+// Which transactions the review lists.
+type viewFilter int
+
+const (
+	filterAll viewFilter = iota
+	filterUnreviewed
+	filterUncategorized
+)
+
+var filterNames = []string{"all", "unreviewed", "uncategorized"}
+
+// What typed characters are entered into.
+type mode int
+
+const (
+	modeBrowse mode = iota
+	modeSearch
+	modeCategory
+	modeTags
+	modeNotes
+)
+
+// Persists the transactions changed and the category rules created since the last save.
+type SaveFunc func(changed []transactions.Transaction, rules []config.CategoryRule) error
+
+// The state of an interactive review of transactions, driven by keys and drawn to a terminal.
+type Session struct {
+	ts   []transactions.Transaction
+	save SaveFunc
+
+	// Indices into `ts` of the listed transactions.
+	view   []int
+	cursor int
+	offset int
+	filter viewFilter
+	query  string
+
+	mode  mode
+	input string
+
+	changed map[int]bool
+	rules   []config.CategoryRule
+	status  string
+	// Whether quitting was requested once with unsaved changes.
+	quitting bool
+	done     bool
+	// The number of list rows of the last render, used for paging.
+	page int
+}
+
+// Starts a review of the transactions that move money, saving with the provided function.
+func NewSession(ts []transactions.Transaction, save SaveFunc) *Session {
+	s := &Session{save: save, changed: map[int]bool{}, page: 10}
+	for _, t := range ts {
+		if t.IsMovement() {
+			s.ts = append(s.ts, t)
+		}
+	}
+	s.refresh()
+	return s
+}
+
+// Whether the review has ended.
+func (s *Session) Done() bool {
+	return s.done
+}
+
+// Whether there are changes or rules that have not been saved.
+func (s *Session) Dirty() bool {
+	return len(s.changed) > 0 || len(s.rules) > 0
+}
+
+// The transactions of the review with the changes made so far.
+func (s *Session) Transactions() []transactions.Transaction {
+	return s.ts
+}
+
+// Rebuilds the listed transactions from the filter and search, keeping the cursor on the same
+// transaction if it is still listed.
+func (s *Session) refresh() {
+	current := -1
+	if s.cursor < len(s.view) {
+		current = s.view[s.cursor]
+	}
+
+	query := strings.ToLower(s.query)
+	s.view = s.view[:0]
+	for i, t := range s.ts {
+		switch {
+		case s.filter == filterUnreviewed && Reviewed(t):
+			continue
+		case s.filter == filterUncategorized && t.Category != "":
+			continue
+		case query != "" && !strings.Contains(searchText(t), query):
+			continue
+		}
+		s.view = append(s.view, i)
+	}
+
+	s.cursor = max(slices.Index(s.view, current), 0)
+}
+
+// The lowercase text a search matches against.
+func searchText(t transactions.Transaction) string {
+	return strings.ToLower(strings.Join([]string{
+		t.Payee, t.AccountHolder, t.Description, t.Category, strings.Join(t.Tags, " "), t.Notes,
+	}, " "))
+}
+
+// The index into `ts` of the transaction under the cursor, or -1 if nothing is listed.
+func (s *Session) current() int {
+	if len(s.view) == 0 {
+		return -1
+	}
+	return s.view[s.cursor]
+}
+
+// Moves the cursor by `n` rows, stopping at the ends of the list.
+func (s *Session) move(n int) {
+	s.cursor = max(min(s.cursor+n, len(s.view)-1), 0)
+}
+
+// Replaces a transaction, remembering it as changed if anything differs.
+func (s *Session) update(i int, t transactions.Transaction) {
+	old := s.ts[i]
+	if old.Category == t.Category && slices.Equal(old.Tags, t.Tags) && old.Notes == t.Notes && Reviewed(old) == Reviewed(t) {
+		return
+	}
+	s.ts[i] = t
+	s.changed[i] = true
+}
+
+// Handles a key pressed on the terminal.
+func (s *Session) HandleKey(k Key) {
+	if k.Code == KeyInterrupt {
+		s.done = true
+		return
+	}
+	if s.mode != modeBrowse {
+		s.handlePrompt(k)
+		return
+	}
+
+	quitting := s.quitting
+	s.quitting = false
+	s.status = ""
+
+	switch {
+	case k.Code == KeyUp || k.Rune == 'k':
+		s.move(-1)
+	case k.Code == KeyDown || k.Rune == 'j':
+		s.move(1)
+	case k.Code == KeyPageUp:
+		s.move(-s.page)
+	case k.Code == KeyPageDown:
+		s.move(s.page)
+	case k.Code == KeyHome || k.Rune == 'g':
+		s.move(-len(s.view))
+	case k.Code == KeyEnd || k.Rune == 'G':
+		s.move(len(s.view))
+	case k.Code == KeyEscape:
+		s.query = ""
+		s.refresh()
+	case k.Rune == '/':
+		s.mode = modeSearch
+	case k.Rune == 'f':
+		s.filter = (s.filter + 1) % viewFilter(len(filterNames))
+		s.refresh()
+	case k.Rune == 'c':
+		if i := s.current(); i >= 0 {
+			s.mode, s.input = modeCategory, s.ts[i].Category
+		}
+	case k.Rune == 't':
+		if i := s.current(); i >= 0 {
+			s.mode, s.input = modeTags, strings.Join(s.ts[i].Tags, " ")
+		}
+	case k.Rune == 'n':
+		if i := s.current(); i >= 0 {
+			s.mode, s.input = modeNotes, s.ts[i].Notes
+		}
+	case k.Rune == ' ' || k.Rune == 'x':
+		if i := s.current(); i >= 0 {
+			s.update(i, withReviewed(s.ts[i], !Reviewed(s.ts[i])))
+			s.move(1)
+		}
+	case k.Rune == 'r':
+		s.createRule()
+	case k.Rune == 'w':
+		s.write()
+	case k.Rune == 'q':
+		if s.Dirty() && !quitting {
+			s.quitting = true
+			s.status = "Unsaved changes: press w to save or q again to quit without saving"
+			return
+		}
+		s.done = true
+	}
+}
+
+// Handles a key while the search, category, tags or notes prompt is open.
+func (s *Session) handlePrompt(k Key) {
+	text := &s.input
+	if s.mode == modeSearch {
+		text = &s.query
+	}
+
+	switch k.Code {
+	case KeyRune:
+		*text += string(k.Rune)
+	case KeyBackspace:
+		if _, size := utf8.DecodeLastRuneInString(*text); size > 0 {
+			*text = (*text)[:len(*text)-size]
+		}
+	case KeyEscape:
+		if s.mode == modeSearch {
+			s.query = ""
+		}
+		s.mode, s.input = modeBrowse, ""
+	case KeyEnter:
+		if i := s.current(); i >= 0 {
+			t := s.ts[i]
+			switch s.mode {
+			case modeCategory:
+				t.Category = strings.TrimSpace(s.input)
+				s.update(i, t)
+			case modeTags:
+				t.Tags = transactions.AddTags(nil, strings.Fields(s.input)...)
+				s.update(i, t)
+			case modeNotes:
+				t.Notes = strings.TrimSpace(s.input)
+				s.update(i, t)
+			}
+		}
+		s.mode, s.input = modeBrowse, ""
+	}
+
+	if s.mode == modeSearch || k.Code == KeyEscape {
+		s.refresh()
+	}
+}
+
+// Creates a rule assigning the category of the current transaction to all transactions of its
+// payee, and applies it to the uncategorized transactions of the review.
+func (s *Session) createRule() {
+	i := s.current()
+	if i < 0 {
+		return
+	}
+	t := s.ts[i]
+	if t.Category == "" {
+		s.status = "Set a category before creating a rule"
+		return
+	}
+
+	field, value := "payee", t.Payee
+	if value == "" {
+		field, value = "accountHolder", t.AccountHolder
+	}
+	if value == "" {
+		s.status = "The transaction has no payee to create a rule for"
+		return
+	}
+
+	raw, err := json.Marshal(config.StringFilter{Field: field, Condition: config.StringEqual, Comparison: value})
+	if err != nil {
+		s.status = fmt.Sprintf("Rule could not be created: %v", err)
+		return
+	}
+	rule := config.CategoryRule{Category: t.Category, Filters: []config.RawFilter{{Raw: raw}}}
+	fs, err := transactions.DecodeFilters(rule.Filters)
+	if err != nil {
+		s.status = fmt.Sprintf("Rule could not be created: %v", err)
+		return
+	}
+	s.rules = append(s.rules, rule)
+
+	applied := 0
+	for j, o := range s.ts {
+		if o.Category == "" && o.Matches(fs) {
+			o.Category = t.Category
+			s.update(j, o)
+			applied++
+		}
+	}
+	s.status = fmt.Sprintf("Rule %s → %s created, applied to %d other transaction(s)", value, t.Category, applied)
+}
+
+// Saves the changed transactions and created rules.
+func (s *Session) write() {
+	if !s.Dirty() {
+		s.status = "No changes to save"
+		return
+	}
+
+	var changed []transactions.Transaction
+	for i, t := range s.ts {
+		if s.changed[i] {
+			changed = append(changed, t)
+		}
+	}
+	if err := s.save(changed, s.rules); err != nil {
+		s.status = fmt.Sprintf("Save failed: %v", err)
+		return
+	}
+	s.status = fmt.Sprintf("Saved %d transaction(s) and %d rule(s)", len(changed), len(s.rules))
+	s.changed = map[int]bool{}
+	s.rules = nil
+}
+
+// The help line shown while browsing.
+const help = "↑↓ move  / search  f filter  c category  t tags  n notes  space reviewed  r rule  w save  q quit"
+
+// Draws the review on a terminal of the provided size with ANSI escape codes.
+func (s *Session) Render(w io.Writer, width, height int) error {
+	width, height = max(width, 20), max(height, 5)
+	s.page = height - 3
+
+	if s.cursor < s.offset {
+		s.offset = s.cursor
+	}
+	if s.cursor >= s.offset+s.page {
+		s.offset = s.cursor - s.page + 1
+	}
+	s.offset = max(min(s.offset, len(s.view)-s.page), 0)
+
+	var b strings.Builder
+	b.WriteString("\x1b[H")
+	line := func(text string, style string) {
+		text = fit(text, width)
+		if style != "" {
+			text = style + text + "\x1b[0m"
+		}
+		b.WriteString(text + "\x1b[K\r\n")
+	}
+
+	reviewed := 0
+	for _, t := range s.ts {
+		if Reviewed(t) {
+			reviewed++
+		}
+	}
+	header := fmt.Sprintf(" Review: %d of %d transaction(s) listed, %d reviewed, filter %s",
+		len(s.view), len(s.ts), reviewed, filterNames[s.filter])
+	if s.query != "" {
+		header += fmt.Sprintf(", search %q", s.query)
+	}
+	if s.Dirty() {
+		header += ", unsaved changes"
+	}
+	line(header, "\x1b[1m")
+
+	for row := range s.page {
+		n := s.offset + row
+		if n >= len(s.view) {
+			line("", "")
+			continue
+		}
+		style := ""
+		if n == s.cursor {
+			style = "\x1b[7m"
+		}
+		line(s.row(s.ts[s.view[n]]), style)
+	}
+
+	detail := ""
+	if i := s.current(); i >= 0 {
+		t := s.ts[i]
+		detail = fmt.Sprintf(" %s | %s | %s | %s", t.ID, t.AccountHolder, t.Description, t.Reference)
+		if t.Notes != "" {
+			detail += " | " + t.Notes
+		}
+	}
+	line(detail, "\x1b[2m")
+
+	switch s.mode {
+	case modeSearch:
+		b.WriteString(fit(" Search: "+s.query, width))
+	case modeCategory:
+		b.WriteString(fit(" Category: "+s.input, width))
+	case modeTags:
+		b.WriteString(fit(" Tags: "+s.input, width))
+	case modeNotes:
+		b.WriteString(fit(" Notes: "+s.input, width))
+	default:
+		if s.status != "" {
+			b.WriteString(fit(" "+s.status, width))
+		} else {
+			b.WriteString(fit(" "+help, width))
+		}
+	}
+	b.WriteString("\x1b[K")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// Formats a transaction as a list row.
+func (s *Session) row(t transactions.Transaction) string {
+	mark := " "
+	if Reviewed(t) {
+		mark = "✓"
+	}
+	payee := t.Payee
+	if payee == "" {
+		payee = t.AccountHolder
+	}
+	return fmt.Sprintf(" %s %s  %s  %12s %-3s  %s  %s  %s",
+		mark,
+		t.Date.Format(ctime.LittleEndianDateOnly),
+		pad(payee, 24),
+		transactions.FormatValue(t.Value, "."),
+		t.Currency,
+		pad(category(t), 16),
+		pad(strings.Join(t.Tags, " "), 14),
+		t.Description,
+	)
+}
+
+// The category shown for a transaction, joining the categories of its splits.
+func category(t transactions.Transaction) string {
+	if len(t.Splits) == 0 {
+		return t.Category
+	}
+	categories := make([]string, len(t.Splits))
+	for i, s := range t.Splits {
+		categories[i] = s.Category
+	}
+	return strings.Join(categories, "+")
+}
+
+// Truncates text to a number of characters.
+func fit(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n])
+}
+
+// Truncates or pads text to exactly a number of characters.
+func pad(s string, n int) string {
+	s = fit(s, n)
+	return s + strings.Repeat(" ", n-utf8.RuneCountInString(s))
+}
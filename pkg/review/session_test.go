@@ -0,0 +1,164 @@
+package review_test
+
+import (
+	"errors"
+	"statements/pkg/config"
+	"statements/pkg/review"
+	"statements/pkg/transactions"
+	"strings"
+	"testing"
+	"time"
+)
+
+func reviewTransactions() []transactions.Transaction {
+	date := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	return []transactions.Transaction{
+		{ID: "1", Date: date, Payee: "Cafe Central", Value: -450, Currency: "EUR", Metadata: map[string]string{"source": "a.csv"}},
+		{ID: "2", Date: date, Payee: "Maxima", Value: -2310, Currency: "EUR"},
+		{ID: "3", Date: date, Kind: transactions.KindClosingBalance, Value: 100000, Currency: "EUR"},
+		{ID: "4", Date: date.AddDate(0, 0, 1), Payee: "Maxima", Value: -1280, Currency: "EUR"},
+		{ID: "5", Date: date.AddDate(0, 0, 2), AccountHolder: "LANDLORD", Value: -70000, Currency: "EUR", Category: "Housing"},
+	}
+}
+
+// Presses the keys of the characters, with carriage return as enter, DEL as backspace and ESC
+// as escape.
+func press(s *review.Session, keys string) {
+	for _, r := range keys {
+		switch r {
+		case '\r':
+			s.HandleKey(review.Key{Code: review.KeyEnter})
+		case 127:
+			s.HandleKey(review.Key{Code: review.KeyBackspace})
+		case 27:
+			s.HandleKey(review.Key{Code: review.KeyEscape})
+		default:
+			s.HandleKey(review.Key{Code: review.KeyRune, Rune: r})
+		}
+	}
+}
+
+// Renders the session and returns the listed rows without escape codes.
+func rows(t *testing.T, s *review.Session) []string {
+	var b strings.Builder
+	if err := s.Render(&b, 120, 10); err != nil {
+		t.Fatalf("Render() unexpected error: %v", err)
+	}
+	lines := strings.Split(b.String(), "\r\n")
+	var listed []string
+	for _, l := range lines[1 : len(lines)-2] {
+		if strings.TrimSpace(strip(l)) != "" {
+			listed = append(listed, strip(l))
+		}
+	}
+	return listed
+}
+
+// Removes ANSI escape sequences from a line.
+func strip(l string) string {
+	var b strings.Builder
+	for i := 0; i < len(l); i++ {
+		if l[i] == 27 {
+			for i < len(l) && !(l[i] >= 'A' && l[i] <= 'Z' || l[i] >= 'a' && l[i] <= 'z') {
+				i++
+			}
+			continue
+		}
+		b.WriteByte(l[i])
+	}
+	return b.String()
+}
+
+func TestSession_Browse(t *testing.T) {
+	s := review.NewSession(reviewTransactions(), nil)
+	if got := rows(t, s); len(got) != 4 {
+		t.Fatalf("rows = %q, want the four transactions that move money", got)
+	}
+
+	press(s, "/maxima")
+	if got := rows(t, s); len(got) != 2 || !strings.Contains(got[0], "Maxima") {
+		t.Errorf("rows after search = %q, want the two Maxima transactions", got)
+	}
+	press(s, "\r\x1b")
+	if got := rows(t, s); len(got) != 4 {
+		t.Errorf("rows after clearing the search = %q, want all transactions", got)
+	}
+
+	press(s, "ff")
+	if got := rows(t, s); len(got) != 3 {
+		t.Errorf("rows with the uncategorized filter = %q, want three", got)
+	}
+	press(s, "f")
+
+	press(s, "G")
+	var b strings.Builder
+	s.Render(&b, 120, 10)
+	if !strings.Contains(b.String(), "LANDLORD | ") {
+		t.Errorf("Render() = %q, want the details of the last transaction", b.String())
+	}
+}
+
+func TestSession_EditAndSave(t *testing.T) {
+	var saved []transactions.Transaction
+	var rules []config.CategoryRule
+	s := review.NewSession(reviewTransactions(), func(ts []transactions.Transaction, rs []config.CategoryRule) error {
+		saved, rules = ts, rs
+		return nil
+	})
+
+	press(s, "cDining\r")
+	press(s, "t #trip  trip\x7f\x7f\x7f\x7fwork\r")
+	press(s, "nSplit with Anna\r")
+	press(s, " ")
+	press(s, "cGroceries\rr")
+
+	ts := s.Transactions()
+	if ts[0].Category != "Dining" || strings.Join(ts[0].Tags, ",") != "trip,work" || !review.Reviewed(ts[0]) {
+		t.Errorf("first transaction = %+v, want categorized, tagged and reviewed", ts[0])
+	}
+	if ts[0].Notes != "Split with Anna" {
+		t.Errorf("notes = %q, want the entered notes", ts[0].Notes)
+	}
+	if ts[1].Category != "Groceries" || ts[2].Category != "Groceries" {
+		t.Errorf("categories = %q, %q, want the rule applied to the other Maxima transaction", ts[1].Category, ts[2].Category)
+	}
+	if ts[3].Category != "Housing" {
+		t.Errorf("category = %q, want unrelated transactions unchanged", ts[3].Category)
+	}
+
+	press(s, "q")
+	if s.Done() {
+		t.Fatal("Done() = true, want quitting with unsaved changes to ask for confirmation")
+	}
+	press(s, "w")
+	if len(saved) != 3 || len(rules) != 1 {
+		t.Fatalf("saved %d transaction(s) and %d rule(s), want 3 and 1", len(saved), len(rules))
+	}
+	if s.Dirty() {
+		t.Error("Dirty() = true after saving")
+	}
+	press(s, "q")
+	if !s.Done() {
+		t.Error("Done() = false, want quitting without changes to end the review")
+	}
+}
+
+func TestSession_SaveFailure(t *testing.T) {
+	s := review.NewSession(reviewTransactions(), func([]transactions.Transaction, []config.CategoryRule) error {
+		return errors.New("disk full")
+	})
+	press(s, " w")
+	if !s.Dirty() {
+		t.Error("Dirty() = false, want changes kept after a failed save")
+	}
+	var b strings.Builder
+	s.Render(&b, 120, 10)
+	if !strings.Contains(b.String(), "disk full") {
+		t.Errorf("Render() = %q, want the save error shown", b.String())
+	}
+
+	s.HandleKey(review.Key{Code: review.KeyInterrupt})
+	if !s.Done() {
+		t.Error("Done() = false, want an interrupt to end the review")
+	}
+}
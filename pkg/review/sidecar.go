@@ -0,0 +1,204 @@
+package review
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"slices"
+	"statements/pkg/config"
+	"statements/pkg/transactions"
+)
+
+// The sidecar file used if none is configured.
+const DefaultSidecar = "review.json"
+
+// The metadata key the review stores the reviewed mark in.
+const reviewedKey = "reviewed"
+
+// Whether a transaction has been marked as reviewed.
+func Reviewed(t transactions.Transaction) bool {
+	return t.Metadata[reviewedKey] == "true"
+}
+
+// Returns the transaction with its reviewed mark replaced.
+func withReviewed(t transactions.Transaction, reviewed bool) transactions.Transaction {
+	t.Metadata = maps.Clone(t.Metadata)
+	if t.Metadata == nil {
+		t.Metadata = map[string]string{}
+	}
+	delete(t.Metadata, reviewedKey)
+	if reviewed {
+		t.Metadata[reviewedKey] = "true"
+	}
+	return t
+}
+
+// The fields of a transaction edited while reviewing, leaving fields that were not edited to the
+// rules.
+type Edit struct {
+	Category *string `json:"category,omitempty"`
+	// The tags added while reviewing.
+	Tags []string `json:"tags,omitempty"`
+	// The tags removed while reviewing, including ones added by tag rules.
+	RemovedTags []string              `json:"removedTags,omitempty"`
+	Notes       *string               `json:"notes,omitempty"`
+	Splits      *[]transactions.Split `json:"splits,omitempty"`
+	Reviewed    *bool                 `json:"reviewed,omitempty"`
+}
+
+// Edits made while reviewing and the category rules created from reviewed transactions.
+//
+// Input files are never modified, so their edits are kept in a separate file and applied to the
+// transactions with the same ID whenever they are loaded.
+type Sidecar struct {
+	path       string
+	Edits      map[string]Edit       `json:"edits,omitempty"`
+	Categories []config.CategoryRule `json:"categories,omitempty"`
+}
+
+// Opens the sidecar file stored at the provided path.
+//
+// A sidecar file that does not exist yet is treated as empty and created on the first save.
+func OpenSidecar(path string) (*Sidecar, error) {
+	s := &Sidecar{path: path, Edits: map[string]Edit{}}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("review file could not be opened: %v", err)
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("review file %s could not be parsed: %v", path, err)
+	}
+	if s.Edits == nil {
+		s.Edits = map[string]Edit{}
+	}
+	return s, nil
+}
+
+// The location of the sidecar file.
+func (s *Sidecar) Path() string {
+	return s.path
+}
+
+// Stores the fields of a transaction that differ from how it was before editing, on top of its
+// earlier edits.
+//
+// The category, notes, splits and reviewed mark are stored when they changed, and tags as the
+// ones added and removed, so fields that were not edited are still assigned by the rules.
+func (s *Sidecar) Record(before, after transactions.Transaction) {
+	e := s.Edits[after.ID]
+	if after.Category != before.Category {
+		e.Category = &after.Category
+	}
+	for _, tag := range after.Tags {
+		if !before.HasTag(tag) {
+			e.Tags = transactions.AddTags(e.Tags, tag)
+			e.RemovedTags = transactions.RemoveTags(e.RemovedTags, tag)
+		}
+	}
+	for _, tag := range before.Tags {
+		if !after.HasTag(tag) {
+			e.Tags = transactions.RemoveTags(e.Tags, tag)
+			e.RemovedTags = transactions.AddTags(e.RemovedTags, tag)
+		}
+	}
+	if after.Notes != before.Notes {
+		e.Notes = &after.Notes
+	}
+	if !slices.Equal(after.Splits, before.Splits) {
+		splits := slices.Clone(after.Splits)
+		e.Splits = &splits
+	}
+	if reviewed := Reviewed(after); reviewed != Reviewed(before) {
+		e.Reviewed = &reviewed
+	}
+	s.Edits[after.ID] = e
+}
+
+// Adds a category rule unless an equal one is already stored.
+func (s *Sidecar) AddRule(r config.CategoryRule) {
+	for _, existing := range s.Categories {
+		if existing.Category == r.Category && slices.EqualFunc(existing.Filters, r.Filters, func(a, b config.RawFilter) bool {
+			return bytes.Equal(a.Raw, b.Raw)
+		}) {
+			return
+		}
+	}
+	s.Categories = append(s.Categories, r)
+}
+
+// Applies the stored edit of a transaction, if there is one.
+//
+// Restoring happens before classification, so fields that were not edited are left to the
+// rules, and tag rules add to the restored tags. Removed tags are removed again by `Untag` once
+// the tag rules have added theirs.
+func (s *Sidecar) Restore(t transactions.Transaction) transactions.Transaction {
+	e, ok := s.Edits[t.ID]
+	if !ok {
+		return t
+	}
+	if e.Category != nil {
+		t.Category = *e.Category
+	}
+	t.Tags = transactions.AddTags(transactions.RemoveTags(t.Tags, e.RemovedTags...), e.Tags...)
+	if e.Notes != nil {
+		t.Notes = *e.Notes
+	}
+	if e.Splits != nil {
+		t.Splits = slices.Clone(*e.Splits)
+	}
+	if e.Reviewed != nil {
+		t = withReviewed(t, *e.Reviewed)
+	}
+	return t
+}
+
+// Applies the stored edits to a stream of transactions.
+func (s *Sidecar) Apply(seq transactions.Seq) transactions.Seq {
+	return s.apply(seq, s.Restore)
+}
+
+// Removes the tags removed while reviewing from a stream of transactions, after tag rules added
+// them back.
+func (s *Sidecar) Untag(seq transactions.Seq) transactions.Seq {
+	return s.apply(seq, func(t transactions.Transaction) transactions.Transaction {
+		if e, ok := s.Edits[t.ID]; ok && len(e.RemovedTags) > 0 {
+			t.Tags = transactions.RemoveTags(t.Tags, e.RemovedTags...)
+		}
+		return t
+	})
+}
+
+// Applies a function to each transaction of a stream.
+func (s *Sidecar) apply(seq transactions.Seq, f func(transactions.Transaction) transactions.Transaction) transactions.Seq {
+	return func(yield func(transactions.Transaction, error) bool) {
+		for t, err := range seq {
+			if err != nil {
+				yield(t, err)
+				return
+			}
+			if !yield(f(t), nil) {
+				return
+			}
+		}
+	}
+}
+
+// Writes the sidecar file.
+func (s *Sidecar) Save() error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("review file could not be encoded: %v", err)
+	}
+	if err := os.WriteFile(s.path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("review file could not be written: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+package review_test
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"statements/pkg/classify"
+	"statements/pkg/config"
+	"statements/pkg/review"
+	"statements/pkg/transactions"
+	"testing"
+)
+
+func TestSidecar_SaveReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), review.DefaultSidecar)
+	s, err := review.OpenSidecar(path)
+	if err != nil {
+		t.Fatalf("OpenSidecar() of a missing file unexpected error: %v", err)
+	}
+
+	rule := config.CategoryRule{
+		Category: "Groceries",
+		Filters:  []config.RawFilter{{Raw: []byte(`{"field":"payee","condition":"EQUAL","comparison":"Maxima"}`)}},
+	}
+	s.AddRule(rule)
+	s.AddRule(rule)
+
+	ts := reviewTransactions()
+	ts[0].Category = "Dining"
+	s.Record(reviewTransactions()[0], ts[0])
+	if err := s.Save(); err != nil {
+		t.Fatalf("Save() unexpected error: %v", err)
+	}
+
+	s, err = review.OpenSidecar(path)
+	if err != nil {
+		t.Fatalf("OpenSidecar() unexpected error: %v", err)
+	}
+	if len(s.Categories) != 1 {
+		t.Fatalf("Categories = %v, want the rule stored once", s.Categories)
+	}
+	c, err := classify.NewClassifier(s.Categories)
+	if err != nil {
+		t.Fatalf("NewClassifier() of the stored rules unexpected error: %v", err)
+	}
+	if got := c.Classify(reviewTransactions()[1]).Category; got != "Groceries" {
+		t.Errorf("Classify().Category = %q with the stored rule, want Groceries", got)
+	}
+
+	got, err := transactions.Collect(s.Apply(transactions.All(reviewTransactions())))
+	if err != nil {
+		t.Fatalf("Apply() unexpected error: %v", err)
+	}
+	if got[0].Category != "Dining" {
+		t.Errorf("Apply()[0].Category = %q, want the recorded Dining", got[0].Category)
+	}
+	if got[1].Category != "" {
+		t.Errorf("Apply()[1].Category = %q, want transactions without edits unchanged", got[1].Category)
+	}
+}
+
+func TestSidecar_RecordTagsNotesAndReviewed(t *testing.T) {
+	s, err := review.OpenSidecar(filepath.Join(t.TempDir(), review.DefaultSidecar))
+	if err != nil {
+		t.Fatalf("OpenSidecar() unexpected error: %v", err)
+	}
+
+	// Tags, notes and the reviewed mark are set through a session, as the review does.
+	session := review.NewSession(reviewTransactions(), func([]transactions.Transaction, []config.CategoryRule) error { return nil })
+	press(session, "ttrip food\rnFlights\r ")
+	originals := map[string]transactions.Transaction{}
+	for _, tr := range reviewTransactions() {
+		originals[tr.ID] = tr
+	}
+	for _, tr := range session.Transactions() {
+		s.Record(originals[tr.ID], tr)
+	}
+
+	original := reviewTransactions()[0]
+	got := s.Restore(original)
+	if !slices.Equal(got.Tags, []string{"trip", "food"}) || got.Notes != "Flights" {
+		t.Errorf("Restore() tags = %v and notes = %q, want the recorded ones", got.Tags, got.Notes)
+	}
+	if !review.Reviewed(got) {
+		t.Error("Reviewed() = false, want the restored mark")
+	}
+	if review.Reviewed(original) || original.Metadata["source"] != "a.csv" {
+		t.Errorf("Restore() modified the metadata of the original transaction: %v", original.Metadata)
+	}
+	if got.Metadata["source"] != "a.csv" {
+		t.Errorf("Restore().Metadata = %v, want other metadata kept", got.Metadata)
+	}
+}
+
+func TestSidecar_RecordSplits(t *testing.T) {
+	path := filepath.Join(t.TempDir(), review.DefaultSidecar)
+	s, err := review.OpenSidecar(path)
+	if err != nil {
+		t.Fatalf("OpenSidecar() unexpected error: %v", err)
+	}
+
+	tr := reviewTransactions()[1]
+	tr.Splits = []transactions.Split{{Category: "Groceries", Value: -1810}, {Category: "Household", Value: -500, Note: "Detergent"}}
+	s.Record(reviewTransactions()[1], tr)
+	if err := s.Save(); err != nil {
+		t.Fatalf("Save() unexpected error: %v", err)
+	}
+
+	s, err = review.OpenSidecar(path)
+	if err != nil {
+		t.Fatalf("OpenSidecar() unexpected error: %v", err)
+	}
+	if got := s.Restore(reviewTransactions()[1]); !slices.Equal(got.Splits, tr.Splits) {
+		t.Errorf("Restore().Splits = %v, want %v", got.Splits, tr.Splits)
+	}
+}
+
+func TestSidecar_RecordEditedFieldsOnly(t *testing.T) {
+	s, err := review.OpenSidecar(filepath.Join(t.TempDir(), review.DefaultSidecar))
+	if err != nil {
+		t.Fatalf("OpenSidecar() unexpected error: %v", err)
+	}
+
+	// The category and tag were assigned by rules when the notes were edited and the tag removed.
+	before := reviewTransactions()[1]
+	before.Category, before.Tags = "Groceries", []string{"food"}
+	after := before
+	after.Tags, after.Notes = nil, "Weekly shopping"
+	s.Record(before, after)
+
+	// The rules have changed since, and still assign the category.
+	loaded := reviewTransactions()[1]
+	loaded.Category = "Food"
+	got := s.Restore(loaded)
+	if got.Category != "Food" || got.Notes != "Weekly shopping" {
+		t.Errorf("Restore() category = %q and notes = %q, want the category of the rules and the edited notes", got.Category, got.Notes)
+	}
+
+	got.Tags = transactions.AddTags(got.Tags, "food", "weekly")
+	untagged, err := transactions.Collect(s.Untag(transactions.All([]transactions.Transaction{got})))
+	if err != nil {
+		t.Fatalf("Untag() unexpected error: %v", err)
+	}
+	if !slices.Equal(untagged[0].Tags, []string{"weekly"}) {
+		t.Errorf("Untag() tags = %v, want the removed tag removed after the rules", untagged[0].Tags)
+	}
+}
+
+func TestOpenSidecar_Invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), review.DefaultSidecar)
+	if err := os.WriteFile(path, []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := review.OpenSidecar(path); err == nil {
+		t.Error("OpenSidecar() expected an error for an invalid file")
+	}
+}
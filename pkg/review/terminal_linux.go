@@ -0,0 +1,53 @@
+//go:build linux
+
+package review
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+// Switches a terminal into raw mode, so keys are read one at a time without being echoed.
+//
+// Returns a function restoring the previous mode.
+func MakeRaw(fd int) (func() error, error) {
+	var old syscall.Termios
+	if err := ioctl(fd, syscall.TCGETS, unsafe.Pointer(&old)); err != nil {
+		return nil, fmt.Errorf("terminal mode could not be read: %v", err)
+	}
+
+	raw := old
+	raw.Iflag &^= syscall.IGNBRK | syscall.BRKINT | syscall.PARMRK | syscall.ISTRIP | syscall.INLCR | syscall.IGNCR | syscall.ICRNL | syscall.IXON
+	raw.Lflag &^= syscall.ECHO | syscall.ECHONL | syscall.ICANON | syscall.ISIG | syscall.IEXTEN
+	raw.Cflag &^= syscall.CSIZE | syscall.PARENB
+	raw.Cflag |= syscall.CS8
+	raw.Cc[syscall.VMIN] = 1
+	raw.Cc[syscall.VTIME] = 0
+	if err := ioctl(fd, syscall.TCSETS, unsafe.Pointer(&raw)); err != nil {
+		return nil, fmt.Errorf("terminal mode could not be set: %v", err)
+	}
+
+	return func() error {
+		if err := ioctl(fd, syscall.TCSETS, unsafe.Pointer(&old)); err != nil {
+			return fmt.Errorf("terminal mode could not be restored: %v", err)
+		}
+		return nil
+	}, nil
+}
+
+// The number of columns and rows of a terminal.
+func Size(fd int) (int, int, error) {
+	var ws struct{ rows, cols, x, y uint16 }
+	if err := ioctl(fd, syscall.TIOCGWINSZ, unsafe.Pointer(&ws)); err != nil {
+		return 0, 0, fmt.Errorf("terminal size could not be read: %v", err)
+	}
+	return int(ws.cols), int(ws.rows), nil
+}
+
+func ioctl(fd int, req uint, arg unsafe.Pointer) error {
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), uintptr(req), uintptr(arg)); errno != 0 {
+		return errno
+	}
+	return nil
+}
@@ -0,0 +1,17 @@
+//go:build !linux
+
+package review
+
+import "errors"
+
+var errUnsupported = errors.New("interactive review is only supported on Linux terminals")
+
+// Switches a terminal into raw mode, which is not supported on this platform.
+func MakeRaw(fd int) (func() error, error) {
+	return nil, errUnsupported
+}
+
+// The number of columns and rows of a terminal, which is not supported on this platform.
+func Size(fd int) (int, int, error) {
+	return 0, 0, errUnsupported
+}
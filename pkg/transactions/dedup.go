@@ -0,0 +1,53 @@
+package transactions
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"statements/pkg/ctime"
+	"strconv"
+	"strings"
+)
+
+// Derives a stable identifier for the transaction.
+//
+// The bank reference is used when present, as it is unique within the bank. Otherwise, the
+// identifier is a hash of the account, kind, date, value, currency, counterparty and
+// description, so the same transaction exported in overlapping statements always receives the
+// same ID, while equal rows of different accounts, such as their balances, do not.
+func (t Transaction) DeriveID() string {
+	if t.BankReference != "" {
+		return t.BankReference
+	}
+
+	parts := []string{
+		t.Account,
+		string(t.Kind),
+		t.Date.Format(ctime.LittleEndianDateOnly),
+		strconv.Itoa(t.Value),
+		t.Currency,
+		t.AccountHolder,
+		t.Description,
+	}
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x1f")))
+	return hex.EncodeToString(sum[:16])
+}
+
+// Removes transactions with duplicate IDs, keeping the first occurrence.
+//
+// Returns the remaining transactions in their original order, as well as the dropped ones.
+func Deduplicate(ts []Transaction) (kept []Transaction, dropped []Transaction) {
+	seen := make(map[string]bool, len(ts))
+	for _, t := range ts {
+		id := t.ID
+		if id == "" {
+			id = t.DeriveID()
+		}
+		if seen[id] {
+			dropped = append(dropped, t)
+			continue
+		}
+		seen[id] = true
+		kept = append(kept, t)
+	}
+	return kept, dropped
+}
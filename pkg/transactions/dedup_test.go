@@ -0,0 +1,84 @@
+package transactions_test
+
+import (
+	"statements/pkg/transactions"
+	"testing"
+	"time"
+)
+
+func TestTransaction_DeriveID(t *testing.T) {
+	base := transactions.Transaction{
+		Kind:          transactions.KindTransaction,
+		Date:          time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		AccountHolder: "TEST SHOP",
+		Description:   "PURCHASE",
+		Value:         -1250,
+		Currency:      "EUR",
+	}
+
+	if base.DeriveID() != base.DeriveID() {
+		t.Error("DeriveID() is not deterministic")
+	}
+
+	withRef := base
+	withRef.BankReference = "2025010101234567"
+	if withRef.DeriveID() != "2025010101234567" {
+		t.Errorf("DeriveID() = %q, want bank reference", withRef.DeriveID())
+	}
+
+	other := base
+	other.Value = -1251
+	if base.DeriveID() == other.DeriveID() {
+		t.Error("DeriveID() returned the same ID for different values")
+	}
+
+	other = base
+	other.Description = "PURCHASE 2"
+	if base.DeriveID() == other.DeriveID() {
+		t.Error("DeriveID() returned the same ID for different descriptions")
+	}
+}
+
+func TestTransaction_DeriveIDAccounts(t *testing.T) {
+	date := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	var ts []transactions.Transaction
+	for _, account := range []string{"LV01HABA0000000000001", "LV02HABA0000000000002"} {
+		for _, tr := range []transactions.Transaction{
+			{Account: account, Kind: transactions.KindOpeningBalance, Date: date, Value: 100000, Currency: "EUR", Description: "Sākuma atlikums"},
+			{Account: account, Kind: transactions.KindTurnover, Date: date, Currency: "EUR", Description: "Apgrozījums"},
+		} {
+			tr.ID = tr.DeriveID()
+			ts = append(ts, tr)
+		}
+	}
+
+	kept, dropped := transactions.Deduplicate(ts)
+	if len(kept) != 4 || len(dropped) != 0 {
+		t.Errorf("Deduplicate() dropped %v, want identical balance rows of different accounts kept", dropped)
+	}
+}
+
+func TestDeduplicate(t *testing.T) {
+	ts := []transactions.Transaction{
+		{ID: "a", Description: "first"},
+		{ID: "b", Description: "second"},
+		{ID: "a", Description: "first again"},
+		{Description: "no ID", Value: 1},
+		{Description: "no ID", Value: 1},
+	}
+
+	kept, dropped := transactions.Deduplicate(ts)
+
+	if len(kept) != 3 {
+		t.Fatalf("Deduplicate() kept %d transactions, want 3", len(kept))
+	}
+	if kept[0].Description != "first" || kept[1].Description != "second" || kept[2].Description != "no ID" {
+		t.Errorf("Deduplicate() kept wrong transactions: %v", kept)
+	}
+	if len(dropped) != 2 {
+		t.Fatalf("Deduplicate() dropped %d transactions, want 2", len(dropped))
+	}
+	if dropped[0].Description != "first again" {
+		t.Errorf("Deduplicate() dropped %q, want %q", dropped[0].Description, "first again")
+	}
+}
@@ -0,0 +1,115 @@
+package transactions
+
+import (
+	"statements/pkg/config"
+	"strings"
+)
+
+// The fields of a normalized transaction available to filters.
+//
+// Besides these, `metadata.<key>` resolves a single metadata entry as a string. Tags resolve to
+// all tags of a transaction, which string filters match individually.
+var FieldMap = config.FieldMap{
+	"id":                  config.FieldTypeString,
+	"kind":                config.FieldTypeString,
+	"account":             config.FieldTypeString,
+	"date":                config.FieldTypeDate,
+	"valueDate":           config.FieldTypeDate,
+	"accountHolder":       config.FieldTypeString,
+	"payee":               config.FieldTypeString,
+	"counterpartyAccount": config.FieldTypeString,
+	"description":         config.FieldTypeString,
+	"value":               config.FieldTypeNumber,
+	"currency":            config.FieldTypeString,
+	"reference":           config.FieldTypeString,
+	"bankReference":       config.FieldTypeString,
+	"documentNumber":      config.FieldTypeString,
+	"bankCode":            config.FieldTypeString,
+	"category":            config.FieldTypeString,
+	"tags":                config.FieldTypeString,
+	"notes":               config.FieldTypeString,
+	"transfer":            config.FieldTypeString,
+}
+
+// Resolves the type of a normalized transaction field, including metadata fields.
+func FieldTypeOf(field string) config.FieldType {
+	if strings.HasPrefix(field, "metadata.") {
+		return config.FieldTypeString
+	}
+	return FieldMap[field]
+}
+
+// Decodes raw filters from the configuration into filters on normalized transactions.
+func DecodeFilters(rfs []config.RawFilter) ([]config.Filter, error) {
+	fs := make([]config.Filter, 0, len(rfs))
+	for _, rf := range rfs {
+		f, err := rf.DecodeWithFieldTypes(FieldTypeOf)
+		if err != nil {
+			return nil, err
+		}
+		fs = append(fs, f)
+	}
+	return fs, nil
+}
+
+// Checks if the transaction matches all filters.
+func (t Transaction) Matches(fs []config.Filter) bool {
+	for _, f := range fs {
+		if !f.Match(t.FieldValue(f.FieldName())) {
+			return false
+		}
+	}
+	return true
+}
+
+// Resolves the value for a given field by name.
+//
+// The value is returned in major units, as filters compare against decimal amounts.
+func (t Transaction) FieldValue(field string) any {
+	if key, ok := strings.CutPrefix(field, "metadata."); ok {
+		return t.Metadata[key]
+	}
+
+	switch field {
+	case "id":
+		return t.ID
+	case "kind":
+		return string(t.Kind)
+	case "account":
+		return t.Account
+	case "date":
+		return t.Date
+	case "valueDate":
+		return t.ValueDate
+	case "accountHolder":
+		return t.AccountHolder
+	case "payee":
+		return t.Payee
+	case "counterpartyAccount":
+		return t.CounterpartyAccount
+	case "description":
+		return t.Description
+	case "value":
+		return float64(t.Value) / 100
+	case "currency":
+		return t.Currency
+	case "reference":
+		return t.Reference
+	case "bankReference":
+		return t.BankReference
+	case "documentNumber":
+		return t.DocumentNumber
+	case "bankCode":
+		return t.BankCode
+	case "category":
+		return t.Category
+	case "tags":
+		return t.Tags
+	case "notes":
+		return t.Notes
+	case "transfer":
+		return t.Transfer
+	}
+
+	return nil
+}
@@ -0,0 +1,214 @@
+package transactions
+
+import (
+	"fmt"
+	"iter"
+	"slices"
+	"time"
+)
+
+// A stream of transactions that may fail while being produced.
+type Seq = iter.Seq2[Transaction, error]
+
+// Streams the transactions of a slice.
+func All(ts []Transaction) Seq {
+	return func(yield func(Transaction, error) bool) {
+		for _, t := range ts {
+			if !yield(t, nil) {
+				return
+			}
+		}
+	}
+}
+
+// Collects a stream of transactions into a slice, stopping at the first error.
+func Collect(seq Seq) ([]Transaction, error) {
+	var ts []Transaction
+	for t, err := range seq {
+		if err != nil {
+			return ts, err
+		}
+		ts = append(ts, t)
+	}
+	return ts, nil
+}
+
+// The error of a stream expected in date order that goes back in time.
+func orderError(prev, next time.Time) error {
+	return fmt.Errorf("transactions are not in date order: %s follows %s", next.Format(time.DateOnly), prev.Format(time.DateOnly))
+}
+
+// Streams transactions in date order, expecting a stream in ascending or descending date order,
+// as banks export statements either oldest or newest first.
+//
+// Ascending streams pass through as they are. Descending streams are collected and reversed,
+// keeping transactions of the same date in the order the bank booked them. A stream that goes
+// back in time after having gone forward fails, as it cannot be put in order without collecting
+// it as a whole.
+func Chronological(seq Seq) Seq {
+	return func(yield func(Transaction, error) bool) {
+		// The transactions held back until the order is known, and all of them once the stream
+		// turns out to be descending.
+		var held []Transaction
+		ascending, descending := false, false
+
+		for t, err := range seq {
+			if err != nil {
+				yield(t, err)
+				return
+			}
+
+			switch {
+			case descending:
+				held = append(held, t)
+				continue
+			case ascending:
+				if t.Date.Before(held[0].Date) {
+					yield(t, orderError(held[0].Date, t.Date))
+					return
+				}
+				held[0] = t
+				if !yield(t, nil) {
+					return
+				}
+				continue
+			case len(held) == 0 || t.Date.Equal(held[0].Date):
+				held = append(held, t)
+				continue
+			case t.Date.Before(held[0].Date):
+				descending = true
+				held = append(held, t)
+				continue
+			}
+
+			ascending = true
+			for _, h := range held {
+				if !yield(h, nil) {
+					return
+				}
+			}
+			held = append(held[:0], t)
+			if !yield(t, nil) {
+				return
+			}
+		}
+
+		if ascending {
+			return
+		}
+		if descending {
+			slices.Reverse(held)
+			slices.SortStableFunc(held, func(a, b Transaction) int { return a.Date.Compare(b.Date) })
+		}
+		for _, t := range held {
+			if !yield(t, nil) {
+				return
+			}
+		}
+	}
+}
+
+// Merges date-ordered streams into a single date-ordered stream.
+//
+// Transactions with the same date are taken from the earlier stream first, so merging is stable.
+// A stream that goes back in time fails the merge, as the merged order would be wrong.
+func MergeByDate(seqs ...Seq) Seq {
+	return func(yield func(Transaction, error) bool) {
+		type head struct {
+			next func() (Transaction, error, bool)
+			t    Transaction
+			ok   bool
+		}
+
+		heads := make([]head, len(seqs))
+		for i, seq := range seqs {
+			next, stop := iter.Pull2(seq)
+			defer stop()
+			heads[i].next = next
+		}
+
+		// Advances a stream, returning false if the merge must stop due to an error.
+		advance := func(h *head) bool {
+			t, err, ok := h.next()
+			if ok && err != nil {
+				yield(t, err)
+				return false
+			}
+			if ok && h.ok && t.Date.Before(h.t.Date) {
+				yield(t, orderError(h.t.Date, t.Date))
+				return false
+			}
+			h.t, h.ok = t, ok
+			return true
+		}
+
+		for i := range heads {
+			if !advance(&heads[i]) {
+				return
+			}
+		}
+
+		for {
+			first := -1
+			for i, h := range heads {
+				if h.ok && (first == -1 || h.t.Date.Before(heads[first].t.Date)) {
+					first = i
+				}
+			}
+			if first == -1 {
+				return
+			}
+
+			if !yield(heads[first].t, nil) {
+				return
+			}
+			if !advance(&heads[first]) {
+				return
+			}
+		}
+	}
+}
+
+// Removes transactions with duplicate IDs from a date-ordered stream, keeping the first
+// occurrence and passing dropped transactions to `dropped`.
+//
+// Only the IDs of the latest date are remembered, so memory use does not grow with the
+// stream. Duplicates always share a date, as the ID is derived from it, unless the bank
+// provides its own reference. A stream that goes back in time fails, as its duplicates would
+// be missed.
+func DeduplicateSeq(seq Seq, dropped func(Transaction)) Seq {
+	return func(yield func(Transaction, error) bool) {
+		var latest time.Time
+		seen := map[string]bool{}
+
+		for t, err := range seq {
+			if err != nil {
+				yield(t, err)
+				return
+			}
+
+			if t.Date.Before(latest) {
+				yield(t, orderError(latest, t.Date))
+				return
+			}
+			if t.Date.After(latest) {
+				latest = t.Date
+				clear(seen)
+			}
+
+			id := t.ID
+			if id == "" {
+				id = t.DeriveID()
+			}
+			if seen[id] {
+				dropped(t)
+				continue
+			}
+			seen[id] = true
+
+			if !yield(t, nil) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package transactions_test
+
+import (
+	"errors"
+	"slices"
+	"statements/pkg/transactions"
+	"testing"
+	"time"
+)
+
+func day(d int) time.Time {
+	return time.Date(2025, 1, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestMergeByDate(t *testing.T) {
+	a := []transactions.Transaction{
+		{ID: "a1", Date: day(1)},
+		{ID: "a3", Date: day(3)},
+	}
+	b := []transactions.Transaction{
+		{ID: "b1", Date: day(1)},
+		{ID: "b2", Date: day(2)},
+		{ID: "b4", Date: day(4)},
+	}
+
+	got, err := transactions.Collect(transactions.MergeByDate(transactions.All(a), transactions.All(b)))
+	if err != nil {
+		t.Fatalf("MergeByDate() unexpected error: %v", err)
+	}
+
+	want := []string{"a1", "b1", "b2", "a3", "b4"}
+	if len(got) != len(want) {
+		t.Fatalf("MergeByDate() returned %d transactions, want %d", len(got), len(want))
+	}
+	for i, id := range want {
+		if got[i].ID != id {
+			t.Errorf("MergeByDate()[%d] = %q, want %q", i, got[i].ID, id)
+		}
+	}
+}
+
+func TestMergeByDate_Error(t *testing.T) {
+	failing := func(yield func(transactions.Transaction, error) bool) {
+		if !yield(transactions.Transaction{ID: "ok", Date: day(1)}, nil) {
+			return
+		}
+		yield(transactions.Transaction{}, errors.New("broken row"))
+	}
+
+	_, err := transactions.Collect(transactions.MergeByDate(failing, transactions.All(nil)))
+	if err == nil {
+		t.Error("MergeByDate() expected error but got none")
+	}
+}
+
+func TestDeduplicateSeq(t *testing.T) {
+	ts := []transactions.Transaction{
+		{ID: "a", Date: day(1)},
+		{ID: "b", Date: day(1)},
+		{ID: "a", Date: day(1)},
+		{ID: "c", Date: day(2)},
+		{ID: "c", Date: day(2)},
+	}
+
+	var dropped []transactions.Transaction
+	got, err := transactions.Collect(transactions.DeduplicateSeq(transactions.All(ts), func(t transactions.Transaction) {
+		dropped = append(dropped, t)
+	}))
+	if err != nil {
+		t.Fatalf("DeduplicateSeq() unexpected error: %v", err)
+	}
+	if len(got) != 3 {
+		t.Errorf("DeduplicateSeq() kept %d transactions, want 3", len(got))
+	}
+	if len(dropped) != 2 {
+		t.Errorf("DeduplicateSeq() dropped %d transactions, want 2", len(dropped))
+	}
+}
+
+func TestChronological(t *testing.T) {
+	tests := []struct {
+		name string
+		ts   []transactions.Transaction
+		want []string
+	}{
+		{
+			name: "ascending",
+			ts:   []transactions.Transaction{{ID: "a", Date: day(1)}, {ID: "b", Date: day(1)}, {ID: "c", Date: day(2)}, {ID: "d", Date: day(2)}},
+			want: []string{"a", "b", "c", "d"},
+		},
+		{
+			name: "descending",
+			ts:   []transactions.Transaction{{ID: "d", Date: day(3)}, {ID: "c", Date: day(2)}, {ID: "b", Date: day(2)}, {ID: "a", Date: day(1)}},
+			want: []string{"a", "b", "c", "d"},
+		},
+		{
+			name: "single date",
+			ts:   []transactions.Transaction{{ID: "a", Date: day(1)}, {ID: "b", Date: day(1)}},
+			want: []string{"a", "b"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := transactions.Collect(transactions.Chronological(transactions.All(tt.ts)))
+			if err != nil {
+				t.Fatalf("Chronological() unexpected error: %v", err)
+			}
+			var ids []string
+			for _, tr := range got {
+				ids = append(ids, tr.ID)
+			}
+			if !slices.Equal(ids, tt.want) {
+				t.Errorf("Chronological() = %v, want %v", ids, tt.want)
+			}
+		})
+	}
+
+	unsorted := []transactions.Transaction{{ID: "a", Date: day(1)}, {ID: "c", Date: day(3)}, {ID: "b", Date: day(2)}}
+	if _, err := transactions.Collect(transactions.Chronological(transactions.All(unsorted))); err == nil {
+		t.Error("Chronological() expected an error for an unsorted stream")
+	}
+}
+
+func TestDateOrder_Descending(t *testing.T) {
+	descending := []transactions.Transaction{
+		{ID: "c", Date: day(3)},
+		{ID: "b", Date: day(2)},
+		{ID: "b", Date: day(2)},
+		{ID: "a", Date: day(1)},
+	}
+
+	if _, err := transactions.Collect(transactions.MergeByDate(transactions.All(descending), transactions.All(nil))); err == nil {
+		t.Error("MergeByDate() expected an error for a descending stream")
+	}
+	if _, err := transactions.Collect(transactions.DeduplicateSeq(transactions.All(descending), func(transactions.Transaction) {})); err == nil {
+		t.Error("DeduplicateSeq() expected an error for a descending stream")
+	}
+
+	var dropped []transactions.Transaction
+	got, err := transactions.Collect(transactions.DeduplicateSeq(transactions.Chronological(transactions.All(descending)), func(t transactions.Transaction) {
+		dropped = append(dropped, t)
+	}))
+	if err != nil {
+		t.Fatalf("DeduplicateSeq() of the ordered stream unexpected error: %v", err)
+	}
+	if len(got) != 3 || got[0].ID != "a" || len(dropped) != 1 {
+		t.Errorf("DeduplicateSeq() of the ordered stream = %v, dropped %v", got, dropped)
+	}
+}
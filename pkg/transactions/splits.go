@@ -0,0 +1,92 @@
+package transactions
+
+import (
+	"errors"
+	"fmt"
+)
+
+// A part of a transaction assigned to its own category, such as the household goods on a
+// supermarket receipt.
+type Split struct {
+	Category string `json:"category"`
+	// The signed value in minor units, with the same sign as the transaction for ordinary splits.
+	Value int    `json:"value"`
+	Note  string `json:"note,omitempty"`
+}
+
+// Checks that the splits of a transaction are complete: at least two splits, each with a
+// category and a value, adding up to the value of the transaction.
+//
+// A transaction without splits is valid.
+func (t Transaction) ValidateSplits() error {
+	if len(t.Splits) == 0 {
+		return nil
+	}
+	if len(t.Splits) == 1 {
+		return errors.New("a split transaction needs at least two splits")
+	}
+
+	sum := 0
+	for i, s := range t.Splits {
+		if s.Category == "" {
+			return fmt.Errorf("split %d has no category", i+1)
+		}
+		if s.Value == 0 {
+			return fmt.Errorf("split %d has no value", i+1)
+		}
+		sum += s.Value
+	}
+	if sum != t.Value {
+		return fmt.Errorf("splits add up to %s instead of the transaction value %s", FormatValue(sum, "."), FormatValue(t.Value, "."))
+	}
+	return nil
+}
+
+// The lines a transaction is reported as: a transaction per split, or the transaction itself if
+// it is not split.
+//
+// Split lines share the fields of the transaction, except for their category, value and note,
+// and their IDs are suffixed with the number of the split, such as `ID/2`.
+func (t Transaction) Lines() []Transaction {
+	if len(t.Splits) == 0 {
+		return []Transaction{t}
+	}
+
+	lines := make([]Transaction, len(t.Splits))
+	for i, s := range t.Splits {
+		l := t
+		l.ID = fmt.Sprintf("%s/%d", t.ID, i+1)
+		l.Category = s.Category
+		l.Value = s.Value
+		if s.Note != "" {
+			l.Notes = s.Note
+		}
+		l.Splits = nil
+		lines[i] = l
+	}
+	return lines
+}
+
+// Replaces split transactions in a stream with their split lines.
+//
+// Splits that do not add up to the transaction value fail the stream, as reports would no longer
+// match the statement.
+func ExpandSplits(seq Seq) Seq {
+	return func(yield func(Transaction, error) bool) {
+		for t, err := range seq {
+			if err != nil {
+				yield(t, err)
+				return
+			}
+			if err := t.ValidateSplits(); err != nil {
+				yield(t, fmt.Errorf("transaction %s: %v", t.ID, err))
+				return
+			}
+			for _, l := range t.Lines() {
+				if !yield(l, nil) {
+					return
+				}
+			}
+		}
+	}
+}
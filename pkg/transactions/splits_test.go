@@ -0,0 +1,75 @@
+package transactions_test
+
+import (
+	"statements/pkg/transactions"
+	"testing"
+)
+
+func splitReceipt() transactions.Transaction {
+	return transactions.Transaction{
+		ID:       "R1",
+		Value:    -5161,
+		Category: "Groceries",
+		Notes:    "Weekly shopping",
+		Splits: []transactions.Split{
+			{Category: "Groceries", Value: -3861},
+			{Category: "Household", Value: -1300, Note: "Detergent"},
+		},
+	}
+}
+
+func TestTransaction_ValidateSplits(t *testing.T) {
+	if err := splitReceipt().ValidateSplits(); err != nil {
+		t.Errorf("ValidateSplits() unexpected error: %v", err)
+	}
+	if err := (transactions.Transaction{Value: -100}).ValidateSplits(); err != nil {
+		t.Errorf("ValidateSplits() of an unsplit transaction unexpected error: %v", err)
+	}
+
+	tests := map[string]func(*transactions.Transaction){
+		"wrong sum":      func(t *transactions.Transaction) { t.Splits[1].Value = -1000 },
+		"single split":   func(t *transactions.Transaction) { t.Splits = t.Splits[:1] },
+		"no category":    func(t *transactions.Transaction) { t.Splits[0].Category = "" },
+		"zero value":     func(t *transactions.Transaction) { t.Splits = append(t.Splits, transactions.Split{Category: "Other"}) },
+		"changed amount": func(t *transactions.Transaction) { t.Value = -6000 },
+	}
+	for name, modify := range tests {
+		tr := splitReceipt()
+		modify(&tr)
+		if err := tr.ValidateSplits(); err == nil {
+			t.Errorf("ValidateSplits() with %s expected an error", name)
+		}
+	}
+}
+
+func TestExpandSplits(t *testing.T) {
+	plain := transactions.Transaction{ID: "P1", Value: -100, Category: "Fees"}
+	got, err := transactions.Collect(transactions.ExpandSplits(transactions.All([]transactions.Transaction{splitReceipt(), plain})))
+	if err != nil {
+		t.Fatalf("ExpandSplits() unexpected error: %v", err)
+	}
+
+	want := []struct {
+		id, category, notes string
+		value               int
+	}{
+		{"R1/1", "Groceries", "Weekly shopping", -3861},
+		{"R1/2", "Household", "Detergent", -1300},
+		{"P1", "Fees", "", -100},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("ExpandSplits() returned %d lines, want %d", len(got), len(want))
+	}
+	for i, w := range want {
+		g := got[i]
+		if g.ID != w.id || g.Category != w.category || g.Notes != w.notes || g.Value != w.value || g.Splits != nil {
+			t.Errorf("line %d = %+v, want %+v", i, g, w)
+		}
+	}
+
+	invalid := splitReceipt()
+	invalid.Splits[0].Value = 0
+	if _, err := transactions.Collect(transactions.ExpandSplits(transactions.All([]transactions.Transaction{invalid}))); err == nil {
+		t.Error("ExpandSplits() expected an error for invalid splits")
+	}
+}
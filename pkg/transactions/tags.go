@@ -0,0 +1,45 @@
+package transactions
+
+import (
+	"slices"
+	"strings"
+)
+
+// Normalizes a tag by trimming spaces and a leading `#`, so `#vacation` and `vacation` are the
+// same tag.
+func NormalizeTag(tag string) string {
+	return strings.TrimPrefix(strings.TrimSpace(tag), "#")
+}
+
+// Whether the transaction has a tag.
+func (t Transaction) HasTag(tag string) bool {
+	return slices.Contains(t.Tags, NormalizeTag(tag))
+}
+
+// Returns the tags with others added in order, skipping empty tags and duplicates.
+//
+// The provided slice is never modified, so tags shared between copies of a transaction stay
+// unchanged.
+func AddTags(tags []string, add ...string) []string {
+	out := slices.Clone(tags)
+	for _, tag := range add {
+		tag = NormalizeTag(tag)
+		if tag != "" && !slices.Contains(out, tag) {
+			out = append(out, tag)
+		}
+	}
+	return out
+}
+
+// Returns the tags without the provided ones.
+func RemoveTags(tags []string, remove ...string) []string {
+	out := slices.Clone(tags)
+	for _, tag := range remove {
+		tag = NormalizeTag(tag)
+		out = slices.DeleteFunc(out, func(t string) bool { return t == tag })
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
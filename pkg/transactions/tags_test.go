@@ -0,0 +1,41 @@
+package transactions_test
+
+import (
+	"slices"
+	"statements/pkg/transactions"
+	"testing"
+)
+
+func TestAddTags(t *testing.T) {
+	tags := []string{"vacation-2025"}
+	got := transactions.AddTags(tags, "#reimbursable", " vacation-2025 ", "", "#")
+	if want := []string{"vacation-2025", "reimbursable"}; !slices.Equal(got, want) {
+		t.Errorf("AddTags() = %v, want %v", got, want)
+	}
+	if len(tags) != 1 {
+		t.Errorf("AddTags() modified the provided tags: %v", tags)
+	}
+}
+
+func TestRemoveTags(t *testing.T) {
+	tags := []string{"vacation-2025", "reimbursable"}
+	if got := transactions.RemoveTags(tags, "#vacation-2025"); !slices.Equal(got, []string{"reimbursable"}) {
+		t.Errorf("RemoveTags() = %v, want [reimbursable]", got)
+	}
+	if got := transactions.RemoveTags(tags, "reimbursable", "vacation-2025"); got != nil {
+		t.Errorf("RemoveTags() = %v, want no tags", got)
+	}
+	if tags[0] != "vacation-2025" || tags[1] != "reimbursable" {
+		t.Errorf("RemoveTags() modified the provided tags: %v", tags)
+	}
+}
+
+func TestTransaction_HasTag(t *testing.T) {
+	tr := transactions.Transaction{Tags: []string{"tax-deductible"}}
+	if !tr.HasTag("#tax-deductible") || !tr.HasTag("tax-deductible") {
+		t.Error("HasTag() = false, want true with and without #")
+	}
+	if tr.HasTag("tax") {
+		t.Error("HasTag() = true for a partial tag")
+	}
+}
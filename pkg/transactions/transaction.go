@@ -1,25 +1,73 @@
 package transactions
 
-import (
-	"fmt"
-	"statements/pkg/ctime"
-	"time"
+import "time"
+
+// The kind of entry a transaction represents within a statement.
+type Kind string
+
+const (
+	KindTransaction    Kind = "transaction"
+	KindOpeningBalance Kind = "opening_balance"
+	KindClosingBalance Kind = "closing_balance"
+	KindTurnover       Kind = "turnover"
+	KindInterest       Kind = "interest"
 )
 
 type Transaction struct {
-	Date          time.Time
-	AccountHolder string
-	Description   string
-	Value         int
-	Currency      string
+	// A stable identifier of the transaction.
+	ID string `json:"id"`
+	// The kind of statement entry.
+	Kind Kind `json:"kind"`
+	// The account number (IBAN) of the account the statement belongs to.
+	Account string `json:"account"`
+	// The booking date.
+	Date time.Time `json:"date"`
+	// The value date, which is the same as the booking date if the bank does not provide it.
+	ValueDate time.Time `json:"valueDate"`
+	// The name of the counterparty.
+	AccountHolder string `json:"accountHolder"`
+	// The canonical name of the counterparty assigned by payee normalization.
+	Payee string `json:"payee,omitempty"`
+	// The account number (IBAN) of the counterparty, if known.
+	CounterpartyAccount string `json:"counterpartyAccount,omitempty"`
+	Description         string `json:"description"`
+	// The signed value in minor units, negative for debits.
+	Value    int    `json:"value"`
+	Currency string `json:"currency"`
+	// The reference number provided by the payer.
+	Reference string `json:"reference,omitempty"`
+	// The unique reference assigned to the transaction by the bank.
+	BankReference string `json:"bankReference,omitempty"`
+	// The number of the payment document.
+	DocumentNumber string `json:"documentNumber,omitempty"`
+	// The bank-specific transaction type code.
+	BankCode string `json:"bankCode,omitempty"`
+	// The category assigned by classification.
+	Category string `json:"category,omitempty"`
+	// Labels assigned by tag rules or by hand, stored without a leading `#`.
+	Tags []string `json:"tags,omitempty"`
+	// Free-text notes added by hand.
+	Notes string `json:"notes,omitempty"`
+	// The parts the transaction is split into, adding up to its value, or none if it is not split.
+	Splits []Split `json:"splits,omitempty"`
+	// The ID of the transaction on another own account this is a transfer to or from, if transfer
+	// matching paired it.
+	Transfer string `json:"transfer,omitempty"`
+	// Bank-specific data that has no dedicated field.
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// Whether the entry is a statement balance.
+func (t Transaction) IsBalance() bool {
+	return t.Kind == KindOpeningBalance || t.Kind == KindClosingBalance
+}
+
+// Whether the entry is a transfer between own accounts, which is neither income nor spending.
+func (t Transaction) IsTransfer() bool {
+	return t.Transfer != ""
 }
 
-func (t Transaction) Csv() []string {
-	return []string{
-		t.Date.Format(ctime.LittleEndianDateOnly),
-		t.AccountHolder,
-		t.Description,
-		fmt.Sprintf("%d,%d", t.Value/100, max(-t.Value%100, t.Value%100)),
-		t.Currency,
-	}
+// Whether the entry moves money, as opposed to statement rows such as balances and turnovers.
+func (t Transaction) IsMovement() bool {
+	return t.Kind == "" || t.Kind == KindTransaction
 }
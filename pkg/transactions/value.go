@@ -0,0 +1,64 @@
+package transactions
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Formats a value in minor units as a decimal number with two fractional digits.
+func FormatValue(v int, sep string) string {
+	return FormatValueGrouped(v, sep, "")
+}
+
+// Formats a value in minor units as a decimal number with two fractional digits, separating
+// groups of thousands with `thousands`.
+func FormatValueGrouped(v int, sep string, thousands string) string {
+	sign := ""
+	if v < 0 {
+		sign = "-"
+		v = -v
+	}
+
+	whole := strconv.Itoa(v / 100)
+	if thousands != "" {
+		var b strings.Builder
+		for i, r := range whole {
+			if i > 0 && (len(whole)-i)%3 == 0 {
+				b.WriteString(thousands)
+			}
+			b.WriteRune(r)
+		}
+		whole = b.String()
+	}
+
+	return fmt.Sprintf("%s%s%s%02d", sign, whole, sep, v%100)
+}
+
+// Parses a decimal number with up to two fractional digits into a value in minor units, ignoring
+// the `thousands` separator.
+func ParseValue(s string, sep string, thousands string) (int, error) {
+	v := strings.TrimSpace(s)
+	if thousands != "" {
+		v = strings.ReplaceAll(v, thousands, "")
+	}
+	sign := 1
+	if rest, ok := strings.CutPrefix(v, "-"); ok {
+		sign, v = -1, rest
+	}
+
+	whole, frac, _ := strings.Cut(v, sep)
+	if len(frac) > 2 {
+		return 0, fmt.Errorf("invalid amount %q: more than two fractional digits", s)
+	}
+	frac += strings.Repeat("0", 2-len(frac))
+	w, err := strconv.ParseUint(whole, 10, 63)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q", s)
+	}
+	f, err := strconv.ParseUint(frac, 10, 63)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q", s)
+	}
+	return sign * int(w*100+f), nil
+}
@@ -0,0 +1,52 @@
+package transactions_test
+
+import (
+	"statements/pkg/transactions"
+	"testing"
+)
+
+func TestFormatValueGrouped(t *testing.T) {
+	tests := []struct {
+		value     int
+		sep       string
+		thousands string
+		want      string
+	}{
+		{150000, ",", "", "1500,00"},
+		{5, ".", "", "0.05"},
+		{-50, ".", "", "-0.50"},
+		{123456789, ".", ",", "1,234,567.89"},
+		{-100000, ",", " ", "-1 000,00"},
+		{99999, ".", ",", "999.99"},
+	}
+	for _, tt := range tests {
+		if got := transactions.FormatValueGrouped(tt.value, tt.sep, tt.thousands); got != tt.want {
+			t.Errorf("FormatValueGrouped(%d, %q, %q) = %q, want %q", tt.value, tt.sep, tt.thousands, got, tt.want)
+		}
+	}
+}
+
+func TestParseValue(t *testing.T) {
+	tests := []struct {
+		s         string
+		sep       string
+		thousands string
+		want      int
+		wantErr   bool
+	}{
+		{"1500,00", ",", "", 150000, false},
+		{"-0.5", ".", "", -50, false},
+		{"12", ".", "", 1200, false},
+		{"1,234,567.89", ".", ",", 123456789, false},
+		{" -1 000,00 ", ",", " ", -100000, false},
+		{"1.234", ".", "", 0, true},
+		{"abc", ".", "", 0, true},
+		{"", ".", "", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := transactions.ParseValue(tt.s, tt.sep, tt.thousands)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseValue(%q, %q, %q) = %d, %v, want %d", tt.s, tt.sep, tt.thousands, got, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package writers
+
+import (
+	"statements/pkg/config"
+	"statements/pkg/transactions"
+	"strings"
+)
+
+// Resolves the accounts of plain-text accounting postings.
+type accountMap struct {
+	cfg config.AccountingConfig
+	// Asset accounts that already received their opening balance.
+	opened map[string]bool
+}
+
+func newAccountMap(c config.Config) accountMap {
+	return accountMap{cfg: c.Accounting.WithDefaults(), opened: map[string]bool{}}
+}
+
+// Checks if a statement balance must be posted as the opening balance of its asset account,
+// rather than asserted. Only the first opening balance of each asset account is posted.
+func (a accountMap) opens(t transactions.Transaction) bool {
+	if t.Kind != transactions.KindOpeningBalance {
+		return false
+	}
+	asset := a.asset(t)
+	if a.opened[asset] {
+		return false
+	}
+	a.opened[asset] = true
+	return true
+}
+
+// The bank asset account of the statement a transaction belongs to.
+func (a accountMap) asset(t transactions.Transaction) string {
+	if acc, ok := a.cfg.AssetAccounts[t.Account]; ok {
+		return acc
+	}
+	return a.cfg.AssetAccount
+}
+
+// The account of the offsetting posting of a transaction.
+//
+// Categories are mapped through the configured accounts. Unmapped categories that already look
+// like an account name are used as is, while others are placed under the income or expense
+// account root depending on the direction of the transaction.
+func (a accountMap) counter(t transactions.Transaction) string {
+	fallback := a.cfg.ExpenseAccount
+	if t.Value > 0 {
+		fallback = a.cfg.IncomeAccount
+	}
+
+	if t.Category == "" {
+		return fallback
+	}
+	if acc, ok := a.cfg.Accounts[t.Category]; ok {
+		return acc
+	}
+	if strings.Contains(t.Category, ":") {
+		return t.Category
+	}
+
+	root, _, _ := strings.Cut(fallback, ":")
+	return root + ":" + t.Category
+}
+
+// The references of a transaction written as accounting metadata, in a stable order.
+func accountingMetadata(t transactions.Transaction) [][2]string {
+	var md [][2]string
+	for _, kv := range [][2]string{
+		{"id", t.ID},
+		{"bankReference", t.BankReference},
+		{"reference", t.Reference},
+		{"documentNumber", t.DocumentNumber},
+		{"bankCode", t.BankCode},
+		{"counterpartyAccount", t.CounterpartyAccount},
+		{"notes", t.Notes},
+		{"transfer", t.Transfer},
+	} {
+		if kv[1] != "" {
+			md = append(md, kv)
+		}
+	}
+	return md
+}
+
+// The title of a balance assertion entry.
+func balanceTitle(t transactions.Transaction) string {
+	if t.Kind == transactions.KindOpeningBalance {
+		return "Opening balance"
+	}
+	return "Closing balance"
+}
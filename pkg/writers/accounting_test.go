@@ -0,0 +1,124 @@
+package writers_test
+
+import (
+	"bytes"
+	"statements/pkg/config"
+	"statements/pkg/transactions"
+	"statements/pkg/writers"
+	"strings"
+	"testing"
+	"time"
+)
+
+func statementWithBalances() []transactions.Transaction {
+	ts := []transactions.Transaction{
+		{
+			Kind:     transactions.KindOpeningBalance,
+			Date:     time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			Value:    10000,
+			Currency: "EUR",
+		},
+	}
+	ts = append(ts, sampleTransactions()...)
+	ts[2].Category = "Groceries"
+	ts[2].BankReference = "2025011600000001"
+	ts[2].Tags = []string{"weekly", "vacation 2025"}
+	ts[2].Notes = "Shared with Anna"
+	return append(ts, transactions.Transaction{
+		Kind:     transactions.KindClosingBalance,
+		Date:     time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC),
+		Value:    158795,
+		Currency: "EUR",
+	})
+}
+
+func writeAccounting(t *testing.T, f writers.Format, c config.Config) string {
+	var buf bytes.Buffer
+	w, err := writers.New(f, &buf, c)
+	if err != nil {
+		t.Fatalf("New() unexpected error: %v", err)
+	}
+	if err := writers.WriteAll(w, transactions.All(statementWithBalances())); err != nil {
+		t.Fatalf("WriteAll() unexpected error: %v", err)
+	}
+	return buf.String()
+}
+
+func TestLedgerWriter(t *testing.T) {
+	c := config.Config{Accounting: config.AccountingConfig{
+		AssetAccount: "Assets:Swedbank",
+		Accounts:     map[string]string{"Groceries": "Expenses:Food:Groceries"},
+	}}
+	out := writeAccounting(t, writers.FormatLedger, c)
+
+	for _, want := range []string{
+		"2025-01-01 * Opening balance\n",
+		"Equity:Opening-Balances",
+		"2025-01-15 * John Doe\n    ; Salary payment\n",
+		"Income:Uncategorized",
+		"Expenses:Food:Groceries",
+		"12.05 EUR\n",
+		"; bankReference: 2025011600000001\n",
+		"    ; :weekly:vacation-2025:\n",
+		"; notes: Shared with Anna\n",
+		"2025-01-31 * Closing balance\n",
+		"0.00 EUR = 1587.95 EUR\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("ledger output does not contain %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestBeancountWriter(t *testing.T) {
+	c := config.Config{Accounting: config.AccountingConfig{AssetAccount: "Assets:my bank"}}
+	out := writeAccounting(t, writers.FormatBeancount, c)
+
+	for _, want := range []string{
+		"2025-01-15 * \"John Doe\" \"Salary payment\"\n",
+		"  Expenses:Groceries",
+		"  bankReference: \"2025011600000001\"\n",
+		" #weekly #vacation-2025\n",
+		"  notes: \"Shared with Anna\"\n",
+		"2025-02-01 balance Assets:My-bank  1587.95 EUR\n",
+		"2025-01-01 open Assets:My-bank\n",
+		"2025-01-01 open Equity:Opening-Balances\n",
+		"2025-01-16 open Expenses:Groceries\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("beancount output does not contain %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestAccountingWriters_Splits(t *testing.T) {
+	tr := sampleTransactions()[1]
+	tr.Category = "Groceries"
+	tr.Splits = []transactions.Split{
+		{Category: "Groceries", Value: -905},
+		{Category: "Household", Value: -300, Note: "Detergent"},
+	}
+
+	for _, tt := range []struct {
+		format writers.Format
+		want   []string
+	}{
+		{writers.FormatLedger, []string{
+			"    Expenses:Groceries", "9.05 EUR\n",
+			"    Expenses:Household", "3.00 EUR  ; Detergent\n",
+			"-12.05 EUR\n",
+		}},
+		{writers.FormatBeancount, []string{
+			"  Expenses:Groceries", "9.05 EUR\n",
+			"3.00 EUR\n    note: \"Detergent\"\n",
+		}},
+		{writers.FormatQif, []string{"LGroceries\nSGroceries\n$-9.05\nSHousehold\n$-3.00\nEDetergent\n^\n"}},
+	} {
+		out := write(t, tt.format, []transactions.Transaction{tr})
+		for _, want := range tt.want {
+			if !strings.Contains(out, want) {
+				t.Errorf("%s output does not contain %q:\n%s", tt.format, want, out)
+			}
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package writers
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"statements/pkg/config"
+	"statements/pkg/transactions"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Writes transactions as beancount directives.
+//
+// The first opening balance of each asset account is posted against the opening balance
+// account, while later opening balances and closing balances are written as balance assertions.
+// Every used account is opened on the date of its first use.
+type BeancountWriter struct {
+	w        *bufio.Writer
+	accounts accountMap
+	opened   map[string]time.Time
+}
+
+// Creates a writer of beancount directives.
+func NewBeancountWriter(w io.Writer, c config.Config) (Writer, error) {
+	return &BeancountWriter{
+		w:        bufio.NewWriter(w),
+		accounts: newAccountMap(c),
+		opened:   map[string]time.Time{},
+	}, nil
+}
+
+// Writes a transaction directive with an offsetting posting, or a posting per split.
+func (w *BeancountWriter) Write(t transactions.Transaction) error {
+	asset := w.account(w.accounts.asset(t), t.Date)
+
+	fmt.Fprintf(w.w, "%s * %s %s", t.Date.Format(time.DateOnly), beancountString(t.AccountHolder), beancountString(t.Description))
+	for _, tag := range t.Tags {
+		fmt.Fprintf(w.w, " #%s", beancountTag(tag))
+	}
+	fmt.Fprintln(w.w)
+	for _, kv := range accountingMetadata(t) {
+		fmt.Fprintf(w.w, "  %s: %s\n", kv[0], beancountString(kv[1]))
+	}
+	for i, l := range t.Lines() {
+		w.posting(w.account(w.accounts.counter(l), t.Date), -l.Value, t.Currency)
+		if len(t.Splits) > 0 && t.Splits[i].Note != "" {
+			fmt.Fprintf(w.w, "    note: %s\n", beancountString(t.Splits[i].Note))
+		}
+	}
+	w.posting(asset, t.Value, t.Currency)
+
+	if _, err := w.w.WriteString("\n"); err != nil {
+		return fmt.Errorf("beancount file could not be written: %v", err)
+	}
+	return nil
+}
+
+// Writes a statement balance as an opening balance or a balance assertion, ignoring other
+// statement rows.
+//
+// Beancount checks balances at the start of the day, so closing balances are asserted on the
+// day after.
+func (w *BeancountWriter) WriteBalance(t transactions.Transaction) error {
+	asset := w.account(w.accounts.asset(t), t.Date)
+
+	switch {
+	case w.accounts.opens(t):
+		equity := w.account(w.accounts.cfg.OpeningBalanceAccount, t.Date)
+		fmt.Fprintf(w.w, "%s * \"Opening balance\"\n", t.Date.Format(time.DateOnly))
+		w.posting(equity, -t.Value, t.Currency)
+		w.posting(asset, t.Value, t.Currency)
+	case t.IsBalance():
+		date := t.Date
+		if t.Kind == transactions.KindClosingBalance {
+			date = date.AddDate(0, 0, 1)
+		}
+		fmt.Fprintf(w.w, "%s balance %s  %s %s\n", date.Format(time.DateOnly), asset, transactions.FormatValue(t.Value, "."), t.Currency)
+	default:
+		return nil
+	}
+
+	if _, err := w.w.WriteString("\n"); err != nil {
+		return fmt.Errorf("beancount file could not be written: %v", err)
+	}
+	return nil
+}
+
+// Opens the used accounts and flushes the buffered directives.
+func (w *BeancountWriter) Close() error {
+	for _, acc := range slices.Sorted(maps.Keys(w.opened)) {
+		fmt.Fprintf(w.w, "%s open %s\n", w.opened[acc].Format(time.DateOnly), acc)
+	}
+	if err := w.w.Flush(); err != nil {
+		return fmt.Errorf("beancount file could not be written: %v", err)
+	}
+	return nil
+}
+
+// Sanitizes an account name, recording the earliest date it is used on.
+func (w *BeancountWriter) account(name string, date time.Time) string {
+	acc := beancountAccount(name)
+	if opened, ok := w.opened[acc]; !ok || date.Before(opened) {
+		w.opened[acc] = date
+	}
+	return acc
+}
+
+// Writes a posting with the amount aligned to the right.
+func (w *BeancountWriter) posting(account string, value int, currency string) {
+	amount := transactions.FormatValue(value, ".") + " " + currency
+	pad := max(2, 48-len(account)-len(amount))
+	fmt.Fprintf(w.w, "  %s%s%s\n", account, strings.Repeat(" ", pad), amount)
+}
+
+// Converts an account name into one accepted by beancount, where every component starts with a
+// capital letter or digit and only contains letters, digits and dashes.
+func beancountAccount(name string) string {
+	parts := strings.Split(name, ":")
+	for i, p := range parts {
+		var b strings.Builder
+		for _, r := range strings.TrimSpace(p) {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' {
+				b.WriteRune(r)
+			} else {
+				b.WriteRune('-')
+			}
+		}
+
+		c := []rune(b.String())
+		if len(c) == 0 {
+			c = []rune("Unknown")
+		}
+		c[0] = unicode.ToUpper(c[0])
+		if !unicode.IsUpper(c[0]) && !unicode.IsDigit(c[0]) {
+			c = append([]rune("X"), c...)
+		}
+		parts[i] = string(c)
+	}
+	return strings.Join(parts, ":")
+}
+
+// Converts a tag into one accepted by beancount, which only allows letters, digits, dashes,
+// underscores, slashes and dots.
+func beancountTag(tag string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("-_/.", r) {
+			return r
+		}
+		return '-'
+	}, tag)
+}
+
+// Quotes a string for beancount.
+func beancountString(s string) string {
+	return strconv.Quote(strings.Join(strings.Fields(s), " "))
+}
@@ -0,0 +1,91 @@
+package writers
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"statements/pkg/config"
+	"statements/pkg/transactions"
+	"strings"
+	"time"
+)
+
+// Resolves a column of tabular output into its text value.
+type column func(t transactions.Transaction) string
+
+// Resolves column names into columns formatted according to the output configuration.
+//
+// Besides the transaction fields, `metadata.<key>` selects a single metadata entry.
+func resolveColumns(o config.OutputConfig) ([]column, error) {
+	layout := o.TimeLayout()
+	date := func(d time.Time) string {
+		if d.IsZero() {
+			return ""
+		}
+		return d.Format(layout)
+	}
+
+	cols := make([]column, len(o.Columns))
+	for i, name := range o.Columns {
+		if key, ok := strings.CutPrefix(name, "metadata."); ok {
+			cols[i] = func(t transactions.Transaction) string { return t.Metadata[key] }
+			continue
+		}
+
+		switch name {
+		case "id":
+			cols[i] = func(t transactions.Transaction) string { return t.ID }
+		case "kind":
+			cols[i] = func(t transactions.Transaction) string { return string(t.Kind) }
+		case "account":
+			cols[i] = func(t transactions.Transaction) string { return t.Account }
+		case "date":
+			cols[i] = func(t transactions.Transaction) string { return date(t.Date) }
+		case "valueDate":
+			cols[i] = func(t transactions.Transaction) string { return date(t.ValueDate) }
+		case "accountHolder":
+			cols[i] = func(t transactions.Transaction) string { return t.AccountHolder }
+		case "payee":
+			cols[i] = func(t transactions.Transaction) string { return t.Payee }
+		case "counterpartyAccount":
+			cols[i] = func(t transactions.Transaction) string { return t.CounterpartyAccount }
+		case "description":
+			cols[i] = func(t transactions.Transaction) string { return t.Description }
+		case "value":
+			cols[i] = func(t transactions.Transaction) string {
+				return transactions.FormatValueGrouped(t.Value, o.Decimal, o.Thousands)
+			}
+		case "currency":
+			cols[i] = func(t transactions.Transaction) string { return t.Currency }
+		case "reference":
+			cols[i] = func(t transactions.Transaction) string { return t.Reference }
+		case "bankReference":
+			cols[i] = func(t transactions.Transaction) string { return t.BankReference }
+		case "documentNumber":
+			cols[i] = func(t transactions.Transaction) string { return t.DocumentNumber }
+		case "bankCode":
+			cols[i] = func(t transactions.Transaction) string { return t.BankCode }
+		case "category":
+			cols[i] = func(t transactions.Transaction) string { return t.Category }
+		case "tags":
+			cols[i] = func(t transactions.Transaction) string { return strings.Join(t.Tags, " ") }
+		case "notes":
+			cols[i] = func(t transactions.Transaction) string { return t.Notes }
+		case "transfer":
+			cols[i] = func(t transactions.Transaction) string { return t.Transfer }
+		case "metadata":
+			cols[i] = func(t transactions.Transaction) string {
+				keys := slices.Sorted(maps.Keys(t.Metadata))
+				pairs := make([]string, len(keys))
+				for i, k := range keys {
+					pairs[i] = k + "=" + t.Metadata[k]
+				}
+				return strings.Join(pairs, ",")
+			}
+		default:
+			return nil, fmt.Errorf("unknown output column %q", name)
+		}
+	}
+
+	return cols, nil
+}
@@ -0,0 +1,90 @@
+package writers
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"statements/pkg/config"
+	"statements/pkg/transactions"
+	"strings"
+)
+
+// Writes transactions as delimiter separated values, formatted by the output configuration.
+type CsvWriter struct {
+	w       *bufio.Writer
+	columns []column
+	opts    config.OutputConfig
+}
+
+// Creates a writer of delimiter separated values, writing the BOM and header if configured.
+func NewCsvWriter(w io.Writer, c config.Config) (Writer, error) {
+	opts := c.Output.WithDefaults()
+
+	cols, err := resolveColumns(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	cw := &CsvWriter{w: bufio.NewWriter(w), columns: cols, opts: opts}
+
+	if opts.Bom {
+		if _, err := cw.w.WriteString("\ufeff"); err != nil {
+			return nil, fmt.Errorf("csv file could not be written: %v", err)
+		}
+	}
+	if opts.Header {
+		if err := cw.writeRow(opts.Columns); err != nil {
+			return nil, err
+		}
+	}
+
+	return cw, nil
+}
+
+// Writes a transaction as a CSV row.
+func (w *CsvWriter) Write(t transactions.Transaction) error {
+	row := make([]string, len(w.columns))
+	for i, col := range w.columns {
+		row[i] = col(t)
+	}
+	return w.writeRow(row)
+}
+
+// Flushes the buffered rows.
+func (w *CsvWriter) Close() error {
+	if err := w.w.Flush(); err != nil {
+		return fmt.Errorf("csv file could not be written: %v", err)
+	}
+	return nil
+}
+
+// Writes a row of fields, quoting them according to the configured style.
+func (w *CsvWriter) writeRow(fields []string) error {
+	for i, f := range fields {
+		if i > 0 {
+			w.w.WriteString(w.opts.Delimiter)
+		}
+		if w.needsQuotes(f) {
+			f = `"` + strings.ReplaceAll(f, `"`, `""`) + `"`
+		}
+		w.w.WriteString(f)
+	}
+	if _, err := w.w.WriteString("\n"); err != nil {
+		return fmt.Errorf("csv file could not be written: %v", err)
+	}
+	return nil
+}
+
+// Checks if a field must be quoted.
+func (w *CsvWriter) needsQuotes(f string) bool {
+	switch w.opts.Quote {
+	case config.QuoteAll:
+		return true
+	case config.QuoteNone:
+		return false
+	default:
+		return strings.Contains(f, w.opts.Delimiter) ||
+			strings.ContainsAny(f, "\"\r\n") ||
+			strings.HasPrefix(f, " ")
+	}
+}
@@ -0,0 +1,73 @@
+package writers_test
+
+import (
+	"bytes"
+	"statements/pkg/config"
+	"statements/pkg/transactions"
+	"statements/pkg/writers"
+	"testing"
+)
+
+func TestCsvWriter(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  config.OutputConfig
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "defaults",
+			output: config.OutputConfig{Columns: []string{"date", "accountHolder", "value", "currency"}},
+			want:   "15.01.2025;John Doe;1500,00;EUR\n16.01.2025;Shop;-12,05;EUR\n",
+		},
+		{
+			name: "english locale with header",
+			output: config.OutputConfig{
+				Columns:    []string{"date", "description", "value"},
+				Header:     true,
+				Delimiter:  ",",
+				DateLayout: "YYYY-MM-DD",
+				Decimal:    ".",
+				Thousands:  ",",
+			},
+			want: "date,description,value\n2025-01-15,Salary payment,\"1,500.00\"\n2025-01-16,Groceries,-12.05\n",
+		},
+		{
+			name: "quote all with BOM",
+			output: config.OutputConfig{
+				Columns: []string{"id", "metadata.source"},
+				Quote:   config.QuoteAll,
+				Bom:     true,
+			},
+			want: "\ufeff\"1\";\"\"\n\"2\";\"\"\n",
+		},
+		{
+			name:    "unknown column",
+			output:  config.OutputConfig{Columns: []string{"balance"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			w, err := writers.NewCsvWriter(&buf, config.Config{Output: tt.output})
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("NewCsvWriter() expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewCsvWriter() unexpected error: %v", err)
+			}
+
+			if err := writers.WriteAll(w, transactions.All(sampleTransactions())); err != nil {
+				t.Fatalf("WriteAll() unexpected error: %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("output = %q, want %q", buf.String(), tt.want)
+			}
+		})
+	}
+}
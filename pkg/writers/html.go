@@ -0,0 +1,304 @@
+package writers
+
+import (
+	"bufio"
+	"cmp"
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"slices"
+	"statements/pkg/analysis"
+	"statements/pkg/config"
+	"statements/pkg/transactions"
+	"strings"
+	"time"
+)
+
+// Writes transactions as a single self-contained HTML report.
+//
+// The report opens with the totals per currency, monthly trend charts drawn as inline SVG and the
+// spending per category, followed by the configured summaries and a searchable table of the
+// transactions. Styles and scripts are inlined so the file can be shared and viewed offline.
+type HtmlWriter struct {
+	w         *bufio.Writer
+	o         config.OutputConfig
+	cols      []column
+	rows      strings.Builder
+	count     int
+	first     time.Time
+	last      time.Time
+	months    *analysis.Summary
+	cats      *analysis.Summary
+	summaries []*analysis.Summary
+}
+
+// Creates a writer of HTML reports, using the columns and summaries of the output configuration.
+func NewHtmlWriter(w io.Writer, c config.Config) (Writer, error) {
+	o := c.Output.WithDefaults()
+	cols, err := resolveColumns(o)
+	if err != nil {
+		return nil, err
+	}
+
+	var summaries []*analysis.Summary
+	for _, s := range o.Summaries {
+		g, err := analysis.ParseGrouping(s)
+		if err != nil {
+			return nil, err
+		}
+		// Months and categories are always part of the report.
+		if g != analysis.GroupMonth && g != analysis.GroupCategory {
+			summaries = append(summaries, analysis.NewSummary(g))
+		}
+	}
+
+	return &HtmlWriter{
+		w:         bufio.NewWriter(w),
+		o:         o,
+		cols:      cols,
+		months:    analysis.NewSummary(analysis.GroupMonth),
+		cats:      analysis.NewSummary(analysis.GroupCategory),
+		summaries: summaries,
+	}, nil
+}
+
+// Adds a transaction to the summaries and the transaction table. Transfers between own accounts
+// are only listed in the table, as they are neither income nor spending.
+func (w *HtmlWriter) Write(t transactions.Transaction) error {
+	if t.IsMovement() {
+		if w.first.IsZero() || t.Date.Before(w.first) {
+			w.first = t.Date
+		}
+		if t.Date.After(w.last) {
+			w.last = t.Date
+		}
+	}
+	if !t.IsTransfer() {
+		w.months.Add(t)
+		w.cats.Add(t)
+		for _, s := range w.summaries {
+			s.Add(t)
+		}
+	}
+
+	w.count++
+	w.rows.WriteString("<tr>")
+	for i, col := range w.cols {
+		if w.o.Columns[i] == "value" {
+			fmt.Fprintf(&w.rows, `<td class="%s">`, htmlAmountClass(t.Value))
+		} else {
+			w.rows.WriteString("<td>")
+		}
+		w.rows.WriteString(html.EscapeString(col(t)))
+		w.rows.WriteString("</td>")
+	}
+	w.rows.WriteString("</tr>\n")
+	return nil
+}
+
+// Writes the complete report.
+func (w *HtmlWriter) Close() error {
+	title := "Bank statements"
+	if !w.first.IsZero() {
+		layout := w.o.TimeLayout()
+		title += fmt.Sprintf(" %s – %s", w.first.Format(layout), w.last.Format(layout))
+	}
+
+	fmt.Fprintf(w.w, "<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>%s</title>\n", html.EscapeString(title))
+	fmt.Fprintf(w.w, "<style>%s</style>\n</head>\n<body>\n<h1>%s</h1>\n", htmlStyle, html.EscapeString(title))
+
+	groups := htmlByCurrency(w.months.Groups())
+	w.w.WriteString("<h2>Overview</h2>\n")
+	w.writeTotals(groups)
+
+	w.w.WriteString("<h2>Monthly trend</h2>\n")
+	for _, currency := range htmlCurrencies(groups) {
+		fmt.Fprintf(w.w, "<h3>%s</h3>\n", html.EscapeString(currency))
+		w.writeTrend(groups[currency])
+	}
+	w.writeSummary(w.months)
+
+	w.w.WriteString("<h2>Categories</h2>\n")
+	w.writeCategories(htmlByCurrency(w.cats.Groups()))
+
+	for _, s := range w.summaries {
+		fmt.Fprintf(w.w, "<h2>%s</h2>\n", xlsxSummaryNames[s.By])
+		w.writeSummary(s)
+	}
+
+	fmt.Fprintf(w.w, "<h2>Transactions</h2>\n<p><input id=\"search\" type=\"search\" placeholder=\"Search\"> "+
+		"<span id=\"matches\">%d</span> of %d transactions</p>\n", w.count, w.count)
+	w.w.WriteString("<table id=\"transactions\">\n<thead><tr>")
+	for _, name := range w.o.Columns {
+		fmt.Fprintf(w.w, "<th>%s</th>", html.EscapeString(name))
+	}
+	w.w.WriteString("</tr></thead>\n<tbody>\n")
+	w.w.WriteString(w.rows.String())
+	w.w.WriteString("</tbody>\n</table>\n")
+
+	fmt.Fprintf(w.w, "<script>%s</script>\n</body>\n</html>\n", htmlScript)
+	if err := w.w.Flush(); err != nil {
+		return fmt.Errorf("html file could not be written: %v", err)
+	}
+	return nil
+}
+
+// Writes the totals over all months, one row per currency.
+func (w *HtmlWriter) writeTotals(groups map[string][]analysis.Group) {
+	w.w.WriteString("<table>\n<thead><tr><th>currency</th><th>income</th><th>spending</th><th>net</th><th>count</th></tr></thead>\n<tbody>\n")
+	for _, currency := range htmlCurrencies(groups) {
+		var total analysis.Totals
+		for _, g := range groups[currency] {
+			total.Income += g.Income
+			total.Spending += g.Spending
+			total.Count += g.Count
+		}
+		fmt.Fprintf(w.w, "<tr><td>%s</td>%s</tr>\n", html.EscapeString(currency), w.totalCells(total))
+	}
+	w.w.WriteString("</tbody>\n</table>\n")
+}
+
+// Writes the totals of a summary as a table, one row per group and currency.
+func (w *HtmlWriter) writeSummary(s *analysis.Summary) {
+	fmt.Fprintf(w.w, "<table>\n<thead><tr><th>%s</th><th>currency</th><th>income</th><th>spending</th><th>net</th><th>count</th></tr></thead>\n<tbody>\n", s.By)
+	for _, g := range s.Groups() {
+		fmt.Fprintf(w.w, "<tr><td>%s</td><td>%s</td>%s</tr>\n",
+			html.EscapeString(g.Key), html.EscapeString(g.Currency), w.totalCells(g.Totals))
+	}
+	w.w.WriteString("</tbody>\n</table>\n")
+}
+
+// Writes the spending per category and its share of all spending, largest first.
+func (w *HtmlWriter) writeCategories(groups map[string][]analysis.Group) {
+	w.w.WriteString("<table>\n<thead><tr><th>category</th><th>currency</th><th>spending</th><th>share</th><th></th></tr></thead>\n<tbody>\n")
+	for _, currency := range htmlCurrencies(groups) {
+		spending := 0
+		var gs []analysis.Group
+		for _, g := range groups[currency] {
+			if g.Spending < 0 {
+				spending += g.Spending
+				gs = append(gs, g)
+			}
+		}
+		slices.SortStableFunc(gs, func(a, b analysis.Group) int { return cmp.Compare(a.Spending, b.Spending) })
+
+		for _, g := range gs {
+			share := float64(g.Spending) / float64(spending) * 100
+			fmt.Fprintf(w.w, "<tr><td>%s</td><td>%s</td><td class=\"num\">%s</td><td class=\"num\">%.1f%%</td>"+
+				"<td class=\"share\"><div style=\"width: %.1f%%\"></div></td></tr>\n",
+				html.EscapeString(g.Key), html.EscapeString(currency), w.amount(-g.Spending), share, share)
+		}
+	}
+	w.w.WriteString("</tbody>\n</table>\n")
+}
+
+// Dimensions of the monthly trend charts.
+const (
+	htmlChartWidth  = 720
+	htmlChartHeight = 240
+	htmlChartMargin = 24
+)
+
+// Draws the income and spending of each month as bars above and below a zero line.
+func (w *HtmlWriter) writeTrend(groups []analysis.Group) {
+	largest := 1
+	for _, g := range groups {
+		largest = max(largest, g.Income, -g.Spending)
+	}
+	slot := float64(htmlChartWidth) / float64(len(groups))
+	zero := float64(htmlChartHeight) / 2
+	scale := (zero - htmlChartMargin) / float64(largest)
+
+	fmt.Fprintf(w.w, "<svg class=\"trend\" viewBox=\"0 0 %d %d\" role=\"img\">\n", htmlChartWidth, htmlChartHeight)
+	// Labels every month while they fit, otherwise at regular steps.
+	step := max(1, int(math.Ceil(60/slot)))
+	for i, g := range groups {
+		x := float64(i) * slot
+		bar := slot * 0.35
+		income, spending := float64(g.Income)*scale, float64(-g.Spending)*scale
+		fmt.Fprintf(w.w, "<rect class=\"income\" x=\"%.1f\" y=\"%.1f\" width=\"%.1f\" height=\"%.1f\"><title>%s income %s</title></rect>\n",
+			x+slot*0.1, zero-income, bar, income, html.EscapeString(g.Key), w.amount(g.Income))
+		fmt.Fprintf(w.w, "<rect class=\"spending\" x=\"%.1f\" y=\"%.1f\" width=\"%.1f\" height=\"%.1f\"><title>%s spending %s</title></rect>\n",
+			x+slot*0.55, zero, bar, spending, html.EscapeString(g.Key), w.amount(g.Spending))
+		if i%step == 0 {
+			fmt.Fprintf(w.w, "<text x=\"%.1f\" y=\"%d\">%s</text>\n", x+slot/2, htmlChartHeight-6, html.EscapeString(g.Key))
+		}
+	}
+	fmt.Fprintf(w.w, "<line x1=\"0\" y1=\"%.1f\" x2=\"%d\" y2=\"%.1f\"/>\n", zero, htmlChartWidth, zero)
+	fmt.Fprintf(w.w, "<text class=\"scale\" x=\"2\" y=\"%d\">%s</text>\n", htmlChartMargin-8, w.amount(largest))
+	w.w.WriteString("</svg>\n")
+}
+
+// The cells of income, spending, net flow and count.
+func (w *HtmlWriter) totalCells(t analysis.Totals) string {
+	return fmt.Sprintf("<td class=\"num\">%s</td><td class=\"num neg\">%s</td><td class=\"%s\">%s</td><td class=\"num\">%d</td>",
+		w.amount(t.Income), w.amount(t.Spending), htmlAmountClass(t.Net()), w.amount(t.Net()), t.Count)
+}
+
+// Formats an amount with the separators of the output configuration.
+func (w *HtmlWriter) amount(v int) string {
+	return transactions.FormatValueGrouped(v, w.o.Decimal, w.o.Thousands)
+}
+
+// The classes of an amount cell, marking negative amounts.
+func htmlAmountClass(v int) string {
+	if v < 0 {
+		return "num neg"
+	}
+	return "num"
+}
+
+// Splits summary groups by currency, keeping their order.
+func htmlByCurrency(gs []analysis.Group) map[string][]analysis.Group {
+	groups := map[string][]analysis.Group{}
+	for _, g := range gs {
+		groups[g.Currency] = append(groups[g.Currency], g)
+	}
+	return groups
+}
+
+// The currencies of split summary groups, ordered alphabetically.
+func htmlCurrencies(groups map[string][]analysis.Group) []string {
+	currencies := make([]string, 0, len(groups))
+	for c := range groups {
+		currencies = append(currencies, c)
+	}
+	slices.Sort(currencies)
+	return currencies
+}
+
+const htmlStyle = `
+body { font-family: system-ui, sans-serif; margin: 2em auto; max-width: 1100px; padding: 0 1em; color: #222; }
+h2 { border-bottom: 1px solid #ddd; margin-top: 2em; }
+table { border-collapse: collapse; margin: 1em 0; }
+th, td { padding: 0.25em 0.75em; text-align: left; border-bottom: 1px solid #eee; }
+th { background: #f5f5f5; }
+.num { text-align: right; font-variant-numeric: tabular-nums; white-space: nowrap; }
+.neg { color: #b03030; }
+.share { width: 200px; }
+.share div { background: #d06060; height: 0.8em; }
+svg.trend { width: 100%; max-width: 720px; height: auto; }
+svg.trend .income { fill: #4a9a5a; }
+svg.trend .spending { fill: #d06060; }
+svg.trend line { stroke: #888; }
+svg.trend text { font-size: 11px; fill: #555; text-anchor: middle; }
+svg.trend text.scale { text-anchor: start; }
+#search { padding: 0.3em; width: 20em; }
+`
+
+// Filters the transaction table to rows containing every search term.
+const htmlScript = `
+const search = document.getElementById("search");
+const rows = document.querySelectorAll("#transactions tbody tr");
+search.addEventListener("input", () => {
+  const terms = search.value.toLowerCase().split(/\s+/).filter(Boolean);
+  let matches = 0;
+  for (const row of rows) {
+    const text = Array.from(row.cells, cell => cell.textContent).join(" ").toLowerCase();
+    row.hidden = !terms.every(term => text.includes(term));
+    if (!row.hidden) matches++;
+  }
+  document.getElementById("matches").textContent = matches;
+});
+`
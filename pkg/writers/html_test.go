@@ -0,0 +1,90 @@
+package writers_test
+
+import (
+	"bytes"
+	"statements/pkg/config"
+	"statements/pkg/transactions"
+	"statements/pkg/writers"
+	"strings"
+	"testing"
+)
+
+func TestHtmlWriter(t *testing.T) {
+	ts := sampleTransactions()
+	ts[1].AccountHolder = "<Shop & Co>"
+	ts[1].Category = "Groceries"
+
+	var buf bytes.Buffer
+	c := config.Config{Output: config.OutputConfig{
+		Columns:   []string{"date", "accountHolder", "value"},
+		Summaries: []string{"counterparty", "month"},
+	}}
+	w, err := writers.New(writers.FormatHtml, &buf, c)
+	if err != nil {
+		t.Fatalf("New() unexpected error: %v", err)
+	}
+	if err := writers.WriteAll(w, transactions.All(ts)); err != nil {
+		t.Fatalf("WriteAll() unexpected error: %v", err)
+	}
+	got := buf.String()
+
+	for _, want := range []string{
+		"<title>Bank statements 15.01.2025 – 16.01.2025</title>",
+		// Totals per currency.
+		`<tr><td>EUR</td><td class="num">1500,00</td><td class="num neg">-12,05</td><td class="num">1487,95</td><td class="num">2</td></tr>`,
+		// The monthly trend chart.
+		`<svg class="trend"`,
+		"<title>2025-01 spending -12,05</title>",
+		// The category breakdown.
+		`<tr><td>Groceries</td><td>EUR</td><td class="num">12,05</td><td class="num">100.0%</td>`,
+		// The configured summary, besides the month summary that is always included.
+		"<h2>Counterparties</h2>",
+		// The escaped transaction table.
+		"<th>date</th><th>accountHolder</th><th>value</th>",
+		`<tr><td>16.01.2025</td><td>&lt;Shop &amp; Co&gt;</td><td class="num neg">-12,05</td></tr>`,
+		`<input id="search"`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("report does not contain %q", want)
+		}
+	}
+
+	if n := strings.Count(got, "<th>month</th>"); n != 1 {
+		t.Errorf("report has %d month summaries, want 1", n)
+	}
+	for _, external := range []string{"http://", "https://", "<link", "src="} {
+		if strings.Contains(got, external) {
+			t.Errorf("report references external assets with %q", external)
+		}
+	}
+}
+
+func TestHtmlWriter_Transfers(t *testing.T) {
+	ts := sampleTransactions()
+	ts = append(ts, transactions.Transaction{
+		ID:            "3",
+		Kind:          transactions.KindTransaction,
+		Date:          ts[1].Date,
+		AccountHolder: "Savings",
+		Value:         -20000,
+		Currency:      "EUR",
+		Transfer:      "4",
+	})
+
+	var buf bytes.Buffer
+	w, err := writers.New(writers.FormatHtml, &buf, config.Config{Output: config.OutputConfig{Columns: []string{"accountHolder", "value"}}})
+	if err != nil {
+		t.Fatalf("New() unexpected error: %v", err)
+	}
+	if err := writers.WriteAll(w, transactions.All(ts)); err != nil {
+		t.Fatalf("WriteAll() unexpected error: %v", err)
+	}
+	got := buf.String()
+
+	if !strings.Contains(got, `<tr><td>EUR</td><td class="num">1500,00</td><td class="num neg">-12,05</td><td class="num">1487,95</td><td class="num">2</td></tr>`) {
+		t.Error("report totals include the transfer")
+	}
+	if !strings.Contains(got, `<tr><td>Savings</td><td class="num neg">-200,00</td></tr>`) {
+		t.Error("report table does not list the transfer")
+	}
+}
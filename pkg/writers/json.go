@@ -0,0 +1,163 @@
+package writers
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"statements/pkg/config"
+	"statements/pkg/transactions"
+	"time"
+)
+
+// The JSON representation of a normalized transaction.
+type jsonTransaction struct {
+	ID                  string            `json:"id"`
+	Kind                transactions.Kind `json:"kind"`
+	Account             string            `json:"account"`
+	Date                string            `json:"date"`
+	ValueDate           string            `json:"valueDate,omitempty"`
+	AccountHolder       string            `json:"accountHolder"`
+	Payee               string            `json:"payee,omitempty"`
+	CounterpartyAccount string            `json:"counterpartyAccount,omitempty"`
+	Description         string            `json:"description"`
+	// The signed amount in major units, such as euros.
+	Amount json.Number `json:"amount"`
+	// The signed amount in minor units, such as cents.
+	AmountMinor    int               `json:"amountMinor"`
+	Currency       string            `json:"currency"`
+	Reference      string            `json:"reference,omitempty"`
+	BankReference  string            `json:"bankReference,omitempty"`
+	DocumentNumber string            `json:"documentNumber,omitempty"`
+	BankCode       string            `json:"bankCode,omitempty"`
+	Category       string            `json:"category,omitempty"`
+	Tags           []string          `json:"tags,omitempty"`
+	Notes          string            `json:"notes,omitempty"`
+	Splits         []jsonSplit       `json:"splits,omitempty"`
+	Transfer       string            `json:"transfer,omitempty"`
+	Metadata       map[string]string `json:"metadata,omitempty"`
+}
+
+// The JSON representation of a split, with amounts like those of transactions.
+type jsonSplit struct {
+	Category    string      `json:"category"`
+	Amount      json.Number `json:"amount"`
+	AmountMinor int         `json:"amountMinor"`
+	Note        string      `json:"note,omitempty"`
+}
+
+// Converts a transaction into its JSON representation.
+func newJsonTransaction(t transactions.Transaction) jsonTransaction {
+	jt := jsonTransaction{
+		ID:                  t.ID,
+		Kind:                t.Kind,
+		Account:             t.Account,
+		Date:                t.Date.Format(time.DateOnly),
+		AccountHolder:       t.AccountHolder,
+		Payee:               t.Payee,
+		CounterpartyAccount: t.CounterpartyAccount,
+		Description:         t.Description,
+		Amount:              json.Number(transactions.FormatValue(t.Value, ".")),
+		AmountMinor:         t.Value,
+		Currency:            t.Currency,
+		Reference:           t.Reference,
+		BankReference:       t.BankReference,
+		DocumentNumber:      t.DocumentNumber,
+		BankCode:            t.BankCode,
+		Category:            t.Category,
+		Tags:                t.Tags,
+		Notes:               t.Notes,
+		Transfer:            t.Transfer,
+		Metadata:            t.Metadata,
+	}
+	if !t.ValueDate.IsZero() {
+		jt.ValueDate = t.ValueDate.Format(time.DateOnly)
+	}
+	for _, s := range t.Splits {
+		jt.Splits = append(jt.Splits, jsonSplit{
+			Category:    s.Category,
+			Amount:      json.Number(transactions.FormatValue(s.Value, ".")),
+			AmountMinor: s.Value,
+			Note:        s.Note,
+		})
+	}
+	return jt
+}
+
+// Writes transactions as an indented JSON array.
+//
+// Transactions are written one at a time, so the array is never held in memory.
+type JsonWriter struct {
+	w     *bufio.Writer
+	count int
+}
+
+// Creates a writer of a JSON array.
+func NewJsonWriter(w io.Writer, c config.Config) (Writer, error) {
+	return &JsonWriter{w: bufio.NewWriter(w)}, nil
+}
+
+// Writes a transaction as an element of the array.
+func (w *JsonWriter) Write(t transactions.Transaction) error {
+	b, err := json.MarshalIndent(newJsonTransaction(t), "  ", "  ")
+	if err != nil {
+		return fmt.Errorf("json could not be encoded: %v", err)
+	}
+
+	sep := ",\n  "
+	if w.count == 0 {
+		sep = "[\n  "
+	}
+	w.count++
+
+	if _, err := w.w.WriteString(sep); err != nil {
+		return fmt.Errorf("json file could not be written: %v", err)
+	}
+	if _, err := w.w.Write(b); err != nil {
+		return fmt.Errorf("json file could not be written: %v", err)
+	}
+	return nil
+}
+
+// Closes the array and flushes the output.
+func (w *JsonWriter) Close() error {
+	end := "\n]\n"
+	if w.count == 0 {
+		end = "[]\n"
+	}
+	if _, err := w.w.WriteString(end); err != nil {
+		return fmt.Errorf("json file could not be written: %v", err)
+	}
+	if err := w.w.Flush(); err != nil {
+		return fmt.Errorf("json file could not be written: %v", err)
+	}
+	return nil
+}
+
+// Writes transactions as newline-delimited JSON, one object per line.
+type NdjsonWriter struct {
+	w   *bufio.Writer
+	enc *json.Encoder
+}
+
+// Creates a writer of newline-delimited JSON.
+func NewNdjsonWriter(w io.Writer, c config.Config) (Writer, error) {
+	bw := bufio.NewWriter(w)
+	return &NdjsonWriter{w: bw, enc: json.NewEncoder(bw)}, nil
+}
+
+// Writes a transaction as a single line.
+func (w *NdjsonWriter) Write(t transactions.Transaction) error {
+	if err := w.enc.Encode(newJsonTransaction(t)); err != nil {
+		return fmt.Errorf("json could not be encoded: %v", err)
+	}
+	return nil
+}
+
+// Flushes the buffered lines.
+func (w *NdjsonWriter) Close() error {
+	if err := w.w.Flush(); err != nil {
+		return fmt.Errorf("json file could not be written: %v", err)
+	}
+	return nil
+}
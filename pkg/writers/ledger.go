@@ -0,0 +1,115 @@
+package writers
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"statements/pkg/config"
+	"statements/pkg/transactions"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Writes transactions as ledger and hledger journal entries.
+//
+// The first opening balance of each asset account is posted against the opening balance
+// account, while later opening balances and closing balances are written as balance assertions.
+type LedgerWriter struct {
+	w        *bufio.Writer
+	accounts accountMap
+}
+
+// Creates a writer of ledger journal entries.
+func NewLedgerWriter(w io.Writer, c config.Config) (Writer, error) {
+	return &LedgerWriter{w: bufio.NewWriter(w), accounts: newAccountMap(c)}, nil
+}
+
+// Writes a transaction as a journal entry with an offsetting posting, or a posting per split.
+func (w *LedgerWriter) Write(t transactions.Transaction) error {
+	payee := t.AccountHolder
+	if payee == "" {
+		payee = t.Description
+	}
+
+	fmt.Fprintf(w.w, "%s * %s\n", t.Date.Format(time.DateOnly), ledgerText(payee))
+	if t.Description != "" && t.Description != payee {
+		fmt.Fprintf(w.w, "    ; %s\n", ledgerText(t.Description))
+	}
+	if len(t.Tags) > 0 {
+		tags := make([]string, len(t.Tags))
+		for i, tag := range t.Tags {
+			tags[i] = ledgerTag(tag)
+		}
+		fmt.Fprintf(w.w, "    ; :%s:\n", strings.Join(tags, ":"))
+	}
+	for _, kv := range accountingMetadata(t) {
+		fmt.Fprintf(w.w, "    ; %s: %s\n", kv[0], ledgerText(kv[1]))
+	}
+	for i, l := range t.Lines() {
+		suffix := ""
+		if len(t.Splits) > 0 && t.Splits[i].Note != "" {
+			suffix = "  ; " + ledgerText(t.Splits[i].Note)
+		}
+		w.posting(w.accounts.counter(l), -l.Value, t.Currency, suffix)
+	}
+	w.posting(w.accounts.asset(t), t.Value, t.Currency, "")
+
+	if _, err := w.w.WriteString("\n"); err != nil {
+		return fmt.Errorf("journal could not be written: %v", err)
+	}
+	return nil
+}
+
+// Writes a statement balance as an opening balance or a balance assertion, ignoring other
+// statement rows.
+func (w *LedgerWriter) WriteBalance(t transactions.Transaction) error {
+	date := t.Date.Format(time.DateOnly)
+
+	switch {
+	case w.accounts.opens(t):
+		fmt.Fprintf(w.w, "%s * Opening balance\n", date)
+		w.posting(w.accounts.cfg.OpeningBalanceAccount, -t.Value, t.Currency, "")
+		w.posting(w.accounts.asset(t), t.Value, t.Currency, "")
+	case t.IsBalance():
+		fmt.Fprintf(w.w, "%s * %s\n", date, balanceTitle(t))
+		w.posting(w.accounts.asset(t), 0, t.Currency, fmt.Sprintf(" = %s %s", transactions.FormatValue(t.Value, "."), t.Currency))
+	default:
+		return nil
+	}
+
+	if _, err := w.w.WriteString("\n"); err != nil {
+		return fmt.Errorf("journal could not be written: %v", err)
+	}
+	return nil
+}
+
+// Flushes the buffered entries.
+func (w *LedgerWriter) Close() error {
+	if err := w.w.Flush(); err != nil {
+		return fmt.Errorf("journal could not be written: %v", err)
+	}
+	return nil
+}
+
+// Writes a posting with the amount aligned to the right.
+func (w *LedgerWriter) posting(account string, value int, currency string, suffix string) {
+	amount := transactions.FormatValue(value, ".") + " " + currency
+	pad := max(2, 48-len(account)-len(amount))
+	fmt.Fprintf(w.w, "    %s%s%s%s\n", account, strings.Repeat(" ", pad), amount, suffix)
+}
+
+// Collapses text into a single line, as journal entries are line based.
+func ledgerText(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// Converts a tag into one usable in a `:tag:` comment, which ends at spaces and colons.
+func ledgerTag(tag string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) || r == ':' {
+			return '-'
+		}
+		return r
+	}, tag)
+}
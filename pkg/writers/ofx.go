@@ -0,0 +1,214 @@
+package writers
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"statements/pkg/config"
+	"statements/pkg/transactions"
+	"strings"
+	"time"
+)
+
+// The OFX date layout without a time component.
+const ofxDate = "20060102"
+
+// The transactions of a single account and currency, written as one OFX statement.
+type ofxStatement struct {
+	account  string
+	currency string
+	ts       []transactions.Transaction
+	opening  *transactions.Transaction
+	closing  *transactions.Transaction
+}
+
+// Writes transactions as an OFX 2.x bank statement response.
+//
+// OFX lists the statement period and balance before the transactions, so transactions are
+// buffered and written on close, with one statement per account and currency.
+type OfxWriter struct {
+	w          *bufio.Writer
+	statements []*ofxStatement
+}
+
+// Creates a writer of OFX 2.x documents.
+func NewOfxWriter(w io.Writer, c config.Config) (Writer, error) {
+	return &OfxWriter{w: bufio.NewWriter(w)}, nil
+}
+
+// Buffers a transaction in the statement of its account.
+func (w *OfxWriter) Write(t transactions.Transaction) error {
+	s := w.statement(t)
+	s.ts = append(s.ts, t)
+	return nil
+}
+
+// Records the statement balances, ignoring other statement rows.
+func (w *OfxWriter) WriteBalance(t transactions.Transaction) error {
+	switch t.Kind {
+	case transactions.KindOpeningBalance:
+		s := w.statement(t)
+		if s.opening == nil {
+			s.opening = &t
+		}
+	case transactions.KindClosingBalance:
+		w.statement(t).closing = &t
+	}
+	return nil
+}
+
+// Writes the buffered statements as a document.
+func (w *OfxWriter) Close() error {
+	now := time.Now().UTC().Format("20060102150405")
+
+	w.w.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="no"?>` + "\n")
+	w.w.WriteString(`<?OFX OFXHEADER="200" VERSION="211" SECURITY="NONE" OLDFILEUID="NONE" NEWFILEUID="NONE"?>` + "\n")
+	w.w.WriteString("<OFX>\n")
+	w.w.WriteString("<SIGNONMSGSRSV1><SONRS>\n")
+	w.w.WriteString("<STATUS><CODE>0</CODE><SEVERITY>INFO</SEVERITY></STATUS>\n")
+	fmt.Fprintf(w.w, "<DTSERVER>%s</DTSERVER>\n<LANGUAGE>ENG</LANGUAGE>\n", now)
+	w.w.WriteString("</SONRS></SIGNONMSGSRSV1>\n")
+	w.w.WriteString("<BANKMSGSRSV1>\n")
+
+	for i, s := range w.statements {
+		w.writeStatement(i+1, s)
+	}
+
+	w.w.WriteString("</BANKMSGSRSV1>\n")
+	w.w.WriteString("</OFX>\n")
+
+	if err := w.w.Flush(); err != nil {
+		return fmt.Errorf("ofx file could not be written: %v", err)
+	}
+	return nil
+}
+
+// Resolves the statement of the transaction's account and currency, creating it if needed.
+func (w *OfxWriter) statement(t transactions.Transaction) *ofxStatement {
+	for _, s := range w.statements {
+		if s.account == t.Account && s.currency == t.Currency {
+			return s
+		}
+	}
+	s := &ofxStatement{account: t.Account, currency: t.Currency}
+	w.statements = append(w.statements, s)
+	return s
+}
+
+// Writes a single statement response.
+func (w *OfxWriter) writeStatement(uid int, s *ofxStatement) {
+	start, end, balance := s.period()
+
+	fmt.Fprintf(w.w, "<STMTTRNRS>\n<TRNUID>%d</TRNUID>\n", uid)
+	w.w.WriteString("<STATUS><CODE>0</CODE><SEVERITY>INFO</SEVERITY></STATUS>\n")
+	w.w.WriteString("<STMTRS>\n")
+	fmt.Fprintf(w.w, "<CURDEF>%s</CURDEF>\n", ofxText(s.currency, 3))
+	w.w.WriteString("<BANKACCTFROM>\n")
+	fmt.Fprintf(w.w, "<BANKID>%s</BANKID>\n", ofxText(bankID(s.account), 9))
+	fmt.Fprintf(w.w, "<ACCTID>%s</ACCTID>\n", ofxText(s.account, 22))
+	w.w.WriteString("<ACCTTYPE>CHECKING</ACCTTYPE>\n")
+	w.w.WriteString("</BANKACCTFROM>\n")
+
+	w.w.WriteString("<BANKTRANLIST>\n")
+	fmt.Fprintf(w.w, "<DTSTART>%s</DTSTART>\n<DTEND>%s</DTEND>\n", start.Format(ofxDate), end.Format(ofxDate))
+	for _, t := range s.ts {
+		trntype := "CREDIT"
+		if t.Value < 0 {
+			trntype = "DEBIT"
+		}
+		name := t.AccountHolder
+		if name == "" {
+			name = t.Description
+		}
+
+		w.w.WriteString("<STMTTRN>\n")
+		fmt.Fprintf(w.w, "<TRNTYPE>%s</TRNTYPE>\n", trntype)
+		fmt.Fprintf(w.w, "<DTPOSTED>%s</DTPOSTED>\n", t.Date.Format(ofxDate))
+		if !t.ValueDate.IsZero() {
+			fmt.Fprintf(w.w, "<DTAVAIL>%s</DTAVAIL>\n", t.ValueDate.Format(ofxDate))
+		}
+		fmt.Fprintf(w.w, "<TRNAMT>%s</TRNAMT>\n", transactions.FormatValue(t.Value, "."))
+		fmt.Fprintf(w.w, "<FITID>%s</FITID>\n", ofxText(ofxFitID(t), 255))
+		if t.Reference != "" {
+			fmt.Fprintf(w.w, "<REFNUM>%s</REFNUM>\n", ofxText(t.Reference, 32))
+		}
+		fmt.Fprintf(w.w, "<NAME>%s</NAME>\n", ofxText(name, 32))
+		if t.Description != "" {
+			fmt.Fprintf(w.w, "<MEMO>%s</MEMO>\n", ofxText(t.Description, 255))
+		}
+		w.w.WriteString("</STMTTRN>\n")
+	}
+	w.w.WriteString("</BANKTRANLIST>\n")
+
+	w.w.WriteString("<LEDGERBAL>\n")
+	fmt.Fprintf(w.w, "<BALAMT>%s</BALAMT>\n", transactions.FormatValue(balance, "."))
+	fmt.Fprintf(w.w, "<DTASOF>%s</DTASOF>\n", end.Format(ofxDate))
+	w.w.WriteString("</LEDGERBAL>\n")
+
+	w.w.WriteString("</STMTRS>\n</STMTTRNRS>\n")
+}
+
+// The date range and closing balance of a statement.
+//
+// Without a closing balance row, the balance is computed from the opening balance, if known,
+// and the transactions.
+func (s *ofxStatement) period() (start time.Time, end time.Time, balance int) {
+	dates := make([]time.Time, 0, len(s.ts)+2)
+	for _, t := range s.ts {
+		dates = append(dates, t.Date)
+		balance += t.Value
+	}
+	if s.opening != nil {
+		dates = append(dates, s.opening.Date)
+		balance += s.opening.Value
+	}
+	if s.closing != nil {
+		dates = append(dates, s.closing.Date)
+		balance = s.closing.Value
+	}
+
+	for i, d := range dates {
+		if i == 0 || d.Before(start) {
+			start = d
+		}
+		if i == 0 || d.After(end) {
+			end = d
+		}
+	}
+	return start, end, balance
+}
+
+// The financial institution transaction ID, which must stay the same across exports.
+func ofxFitID(t transactions.Transaction) string {
+	if t.ID != "" {
+		return t.ID
+	}
+	return t.DeriveID()
+}
+
+// The bank identifier of an account, taken from the bank code of an IBAN if possible.
+func bankID(account string) string {
+	if len(account) >= 8 && ibanPrefix(account) {
+		return account[4:8]
+	}
+	return "UNKNOWN"
+}
+
+// Checks if an account number starts like an IBAN.
+func ibanPrefix(s string) bool {
+	return s[0] >= 'A' && s[0] <= 'Z' && s[1] >= 'A' && s[1] <= 'Z' &&
+		s[2] >= '0' && s[2] <= '9' && s[3] >= '0' && s[3] <= '9'
+}
+
+// Escapes text for an OFX element, limiting it to the maximum length of the element.
+func ofxText(s string, limit int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if r := []rune(s); len(r) > limit {
+		s = string(r[:limit])
+	}
+
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
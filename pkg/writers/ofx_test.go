@@ -0,0 +1,88 @@
+package writers_test
+
+import (
+	"encoding/xml"
+	"statements/pkg/config"
+	"statements/pkg/transactions"
+	"statements/pkg/writers"
+	"testing"
+)
+
+type ofxDocument struct {
+	Statements []struct {
+		Currency string `xml:"STMTRS>CURDEF"`
+		BankID   string `xml:"STMTRS>BANKACCTFROM>BANKID"`
+		Start    string `xml:"STMTRS>BANKTRANLIST>DTSTART"`
+		End      string `xml:"STMTRS>BANKTRANLIST>DTEND"`
+		Entries  []struct {
+			Type   string `xml:"TRNTYPE"`
+			Amount string `xml:"TRNAMT"`
+			FitID  string `xml:"FITID"`
+			Name   string `xml:"NAME"`
+		} `xml:"STMTRS>BANKTRANLIST>STMTTRN"`
+		Balance string `xml:"STMTRS>LEDGERBAL>BALAMT"`
+	} `xml:"BANKMSGSRSV1>STMTTRNRS"`
+}
+
+func TestOfxWriter(t *testing.T) {
+	out := writeAccounting(t, writers.FormatOfx, config.Config{})
+	var doc ofxDocument
+	if err := xml.Unmarshal([]byte(out), &doc); err != nil {
+		t.Fatalf("OFX output could not be parsed: %v\n%s", err, out)
+	}
+
+	if len(doc.Statements) != 1 {
+		t.Fatalf("OFX output has %d statements, want 1", len(doc.Statements))
+	}
+	s := doc.Statements[0]
+	if s.Currency != "EUR" || s.Start != "20250101" || s.End != "20250131" {
+		t.Errorf("statement = %s %s-%s, want EUR 20250101-20250131", s.Currency, s.Start, s.End)
+	}
+	if s.BankID != "UNKNOWN" {
+		t.Errorf("bank ID = %q, want UNKNOWN for an unknown account", s.BankID)
+	}
+	if s.Balance != "1587.95" {
+		t.Errorf("balance = %s, want 1587.95", s.Balance)
+	}
+	if len(s.Entries) != 2 {
+		t.Fatalf("statement has %d transactions, want 2", len(s.Entries))
+	}
+	if s.Entries[0].Type != "CREDIT" || s.Entries[0].Amount != "1500.00" {
+		t.Errorf("first transaction = %s %s, want CREDIT 1500.00", s.Entries[0].Type, s.Entries[0].Amount)
+	}
+	if s.Entries[1].Type != "DEBIT" || s.Entries[1].Amount != "-12.05" {
+		t.Errorf("second transaction = %s %s, want DEBIT -12.05", s.Entries[1].Type, s.Entries[1].Amount)
+	}
+	if s.Entries[1].FitID != "2" {
+		t.Errorf("FITID = %q, want 2", s.Entries[1].FitID)
+	}
+}
+
+func TestQifWriter(t *testing.T) {
+	out := write(t, writers.FormatQif, sampleTransactions())
+	want := "!Type:Bank\n" +
+		"D01/15/2025\nT1500.00\nPJohn Doe\nMSalary payment\n^\n" +
+		"D01/16/2025\nT-12.05\nPShop\nMGroceries\n^\n"
+	if out != want {
+		t.Errorf("QIF output = %q, want %q", out, want)
+	}
+}
+
+func TestQifWriter_StatementRows(t *testing.T) {
+	ts := sampleTransactions()
+	ts = append([]transactions.Transaction{
+		{ID: "o", Kind: transactions.KindOpeningBalance, Date: ts[0].Date, Value: 100000, Currency: "EUR"},
+	}, ts...)
+	ts = append(ts,
+		transactions.Transaction{ID: "t", Kind: transactions.KindTurnover, Date: ts[2].Date, Value: 0, Currency: "EUR"},
+		transactions.Transaction{ID: "c", Kind: transactions.KindClosingBalance, Date: ts[2].Date, Value: 248795, Currency: "EUR"},
+	)
+
+	out := write(t, writers.FormatQif, ts)
+	want := "!Type:Bank\n" +
+		"D01/15/2025\nT1500.00\nPJohn Doe\nMSalary payment\n^\n" +
+		"D01/16/2025\nT-12.05\nPShop\nMGroceries\n^\n"
+	if out != want {
+		t.Errorf("QIF output = %q, want only the transactions %q", out, want)
+	}
+}
@@ -0,0 +1,74 @@
+package writers
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"statements/pkg/config"
+	"statements/pkg/transactions"
+	"strings"
+)
+
+// Writes transactions as a QIF bank account list.
+//
+// QIF has no notion of multiple accounts in a bank list, so all transactions are written as
+// belonging to a single account.
+type QifWriter struct {
+	w *bufio.Writer
+}
+
+// Creates a writer of QIF bank transactions.
+func NewQifWriter(w io.Writer, c config.Config) (Writer, error) {
+	bw := bufio.NewWriter(w)
+	if _, err := bw.WriteString("!Type:Bank\n"); err != nil {
+		return nil, fmt.Errorf("qif file could not be written: %v", err)
+	}
+	return &QifWriter{w: bw}, nil
+}
+
+// Writes a transaction as a QIF record, with a split line per split.
+//
+// Statement rows that do not move money, such as balances and turnovers, are skipped, as QIF
+// would import them as transactions.
+func (w *QifWriter) Write(t transactions.Transaction) error {
+	if !t.IsMovement() {
+		return nil
+	}
+	fmt.Fprintf(w.w, "D%s\n", t.Date.Format("01/02/2006"))
+	fmt.Fprintf(w.w, "T%s\n", transactions.FormatValue(t.Value, "."))
+	if t.AccountHolder != "" {
+		fmt.Fprintf(w.w, "P%s\n", qifText(t.AccountHolder))
+	}
+	if t.Description != "" {
+		fmt.Fprintf(w.w, "M%s\n", qifText(t.Description))
+	}
+	if t.DocumentNumber != "" {
+		fmt.Fprintf(w.w, "N%s\n", qifText(t.DocumentNumber))
+	}
+	if t.Category != "" {
+		fmt.Fprintf(w.w, "L%s\n", qifText(t.Category))
+	}
+	for _, s := range t.Splits {
+		fmt.Fprintf(w.w, "S%s\n$%s\n", qifText(s.Category), transactions.FormatValue(s.Value, "."))
+		if s.Note != "" {
+			fmt.Fprintf(w.w, "E%s\n", qifText(s.Note))
+		}
+	}
+	if _, err := w.w.WriteString("^\n"); err != nil {
+		return fmt.Errorf("qif file could not be written: %v", err)
+	}
+	return nil
+}
+
+// Flushes the buffered records.
+func (w *QifWriter) Close() error {
+	if err := w.w.Flush(); err != nil {
+		return fmt.Errorf("qif file could not be written: %v", err)
+	}
+	return nil
+}
+
+// Collapses text into a single line, as QIF fields are line based.
+func qifText(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
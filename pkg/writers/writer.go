@@ -0,0 +1,131 @@
+package writers
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"statements/pkg/config"
+	"statements/pkg/transactions"
+	"strings"
+)
+
+// Writes normalized transactions in a specific output format.
+type Writer interface {
+	// Writes a single transaction.
+	Write(t transactions.Transaction) error
+	// Writes any trailing data and flushes the output. The underlying writer is not closed.
+	Close() error
+}
+
+// A writer that also records statement balances, such as for balance assertions.
+//
+// Statement balances are kept regardless of the configured filters when writing with it.
+type BalanceWriter interface {
+	Writer
+	// Writes a statement row that does not move money, such as a balance.
+	WriteBalance(t transactions.Transaction) error
+}
+
+// Creates a writer for a format, writing to `w` and configured by `c`.
+type Constructor func(w io.Writer, c config.Config) (Writer, error)
+
+type Format string
+
+const (
+	FormatCsv       Format = "csv"
+	FormatJson      Format = "json"
+	FormatNdjson    Format = "ndjson"
+	FormatLedger    Format = "ledger"
+	FormatHledger   Format = "hledger"
+	FormatBeancount Format = "beancount"
+	FormatOfx       Format = "ofx"
+	FormatQif       Format = "qif"
+	FormatXlsx      Format = "xlsx"
+	FormatHtml      Format = "html"
+)
+
+var constructors = map[Format]Constructor{}
+
+// Maps file extensions to the format they are written in.
+var extensions = map[string]Format{}
+
+// Registers a writer for a format, used for output files with any of the provided extensions.
+//
+// Registering a format again replaces the previous writer.
+func Register(f Format, c Constructor, exts ...string) {
+	constructors[f] = c
+	for _, ext := range exts {
+		extensions[strings.ToLower(ext)] = f
+	}
+}
+
+func init() {
+	Register(FormatCsv, NewCsvWriter, ".csv")
+	Register(FormatJson, NewJsonWriter, ".json")
+	Register(FormatNdjson, NewNdjsonWriter, ".ndjson", ".jsonl")
+	Register(FormatLedger, NewLedgerWriter, ".ledger")
+	Register(FormatHledger, NewLedgerWriter, ".hledger", ".journal")
+	Register(FormatBeancount, NewBeancountWriter, ".beancount", ".bean")
+	Register(FormatOfx, NewOfxWriter, ".ofx")
+	Register(FormatQif, NewQifWriter, ".qif")
+	Register(FormatXlsx, NewXlsxWriter, ".xlsx")
+	Register(FormatHtml, NewHtmlWriter, ".html", ".htm")
+}
+
+// The registered formats, ordered alphabetically.
+func Formats() []Format {
+	fs := make([]Format, 0, len(constructors))
+	for f := range constructors {
+		fs = append(fs, f)
+	}
+	slices.Sort(fs)
+	return fs
+}
+
+// Parses a string into a registered format.
+func ParseFormat(v string) (Format, error) {
+	f := Format(strings.ToLower(v))
+	if _, ok := constructors[f]; !ok {
+		return "", fmt.Errorf("unknown output format %q, must be one of %v", v, Formats())
+	}
+	return f, nil
+}
+
+// Resolves the format of an output file from its extension, defaulting to CSV.
+func FormatFromPath(path string) Format {
+	if f, ok := extensions[strings.ToLower(filepath.Ext(path))]; ok {
+		return f
+	}
+	return FormatCsv
+}
+
+// Creates a writer for the provided format.
+func New(f Format, w io.Writer, c config.Config) (Writer, error) {
+	constructor, ok := constructors[f]
+	if !ok {
+		return nil, fmt.Errorf("unknown output format %q, must be one of %v", f, Formats())
+	}
+	return constructor(w, c)
+}
+
+// Writes a stream of transactions and closes the writer.
+//
+// Statement rows that do not move money are passed to `WriteBalance` if the writer records them.
+func WriteAll(w Writer, ts transactions.Seq) error {
+	bw, balances := w.(BalanceWriter)
+	for t, err := range ts {
+		if err != nil {
+			return err
+		}
+		if balances && !t.IsMovement() {
+			err = bw.WriteBalance(t)
+		} else {
+			err = w.Write(t)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return w.Close()
+}
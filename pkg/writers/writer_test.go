@@ -0,0 +1,149 @@
+package writers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"statements/pkg/config"
+	"statements/pkg/transactions"
+	"statements/pkg/writers"
+	"strings"
+	"testing"
+	"time"
+)
+
+func sampleTransactions() []transactions.Transaction {
+	return []transactions.Transaction{
+		{
+			ID:            "1",
+			Kind:          transactions.KindTransaction,
+			Date:          time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC),
+			AccountHolder: "John Doe",
+			Description:   "Salary payment",
+			Value:         150000,
+			Currency:      "EUR",
+		},
+		{
+			ID:            "2",
+			Kind:          transactions.KindTransaction,
+			Date:          time.Date(2025, 1, 16, 0, 0, 0, 0, time.UTC),
+			AccountHolder: "Shop",
+			Description:   "Groceries",
+			Value:         -1205,
+			Currency:      "EUR",
+		},
+	}
+}
+
+// Writes the sample transactions in the provided format.
+func write(t *testing.T, f writers.Format, ts []transactions.Transaction) string {
+	var buf bytes.Buffer
+	w, err := writers.New(f, &buf, config.Config{})
+	if err != nil {
+		t.Fatalf("New() unexpected error: %v", err)
+	}
+	if err := writers.WriteAll(w, transactions.All(ts)); err != nil {
+		t.Fatalf("WriteAll() unexpected error: %v", err)
+	}
+	return buf.String()
+}
+
+func TestFormatFromPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want writers.Format
+	}{
+		{"output.csv", writers.FormatCsv},
+		{"output.JSON", writers.FormatJson},
+		{"output.ndjson", writers.FormatNdjson},
+		{"output.jsonl", writers.FormatNdjson},
+		{"output", writers.FormatCsv},
+	}
+	for _, tt := range tests {
+		if got := writers.FormatFromPath(tt.path); got != tt.want {
+			t.Errorf("FormatFromPath(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	if f, err := writers.ParseFormat("NDJSON"); err != nil || f != writers.FormatNdjson {
+		t.Errorf("ParseFormat(NDJSON) = %q, %v, want ndjson", f, err)
+	}
+	if _, err := writers.ParseFormat("pdf"); err == nil {
+		t.Error("ParseFormat(pdf) expected error but got none")
+	}
+}
+
+func TestJsonWriter(t *testing.T) {
+	var got []map[string]any
+	if err := json.Unmarshal([]byte(write(t, writers.FormatJson, sampleTransactions())), &got); err != nil {
+		t.Fatalf("JSON output could not be parsed: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("JSON output has %d elements, want 2", len(got))
+	}
+	if got[1]["date"] != "2025-01-16" {
+		t.Errorf("date = %v, want 2025-01-16", got[1]["date"])
+	}
+	if got[1]["amount"] != -12.05 {
+		t.Errorf("amount = %v, want -12.05", got[1]["amount"])
+	}
+	if got[1]["amountMinor"] != float64(-1205) {
+		t.Errorf("amountMinor = %v, want -1205", got[1]["amountMinor"])
+	}
+
+	split := sampleTransactions()[1:]
+	split[0].Splits = []transactions.Split{{Category: "Groceries", Value: -905}, {Category: "Household", Value: -300, Note: "Detergent"}}
+	if out := write(t, writers.FormatJson, split); !strings.Contains(out, `"category": "Household",
+        "amount": -3.00,
+        "amountMinor": -300,
+        "note": "Detergent"`) {
+		t.Errorf("JSON output does not contain the splits:\n%s", out)
+	}
+
+	if out := write(t, writers.FormatJson, nil); strings.TrimSpace(out) != "[]" {
+		t.Errorf("empty JSON output = %q, want []", out)
+	}
+}
+
+func TestNdjsonWriter(t *testing.T) {
+	lines := strings.Split(strings.TrimSpace(write(t, writers.FormatNdjson, sampleTransactions())), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("NDJSON output has %d lines, want 2", len(lines))
+	}
+	for i, line := range lines {
+		var v map[string]any
+		if err := json.Unmarshal([]byte(line), &v); err != nil {
+			t.Errorf("line %d could not be parsed: %v", i, err)
+		}
+	}
+}
+
+type countWriter struct {
+	w     io.Writer
+	count int
+}
+
+func (c *countWriter) Write(t transactions.Transaction) error {
+	c.count++
+	return nil
+}
+
+func (c *countWriter) Close() error {
+	_, err := io.WriteString(c.w, strings.Repeat("x", c.count))
+	return err
+}
+
+func TestRegister(t *testing.T) {
+	writers.Register("count", func(w io.Writer, c config.Config) (writers.Writer, error) {
+		return &countWriter{w: w}, nil
+	}, ".count")
+
+	if f := writers.FormatFromPath("out.count"); f != "count" {
+		t.Errorf("FormatFromPath() = %q, want count", f)
+	}
+	if out := write(t, "count", sampleTransactions()); out != "xx" {
+		t.Errorf("custom writer output = %q, want xx", out)
+	}
+}
@@ -0,0 +1,382 @@
+package writers
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"slices"
+	"statements/pkg/analysis"
+	"statements/pkg/config"
+	"statements/pkg/transactions"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The styles of spreadsheet cells, indexing the cell formats of the style sheet.
+const (
+	xlsxStyleText = iota
+	xlsxStyleDate
+	xlsxStyleAmount
+	xlsxStyleHeader
+	// The first of the amount styles of each currency, in the order the currencies are used.
+	xlsxStyleCurrency
+)
+
+// The day spreadsheet date serial numbers count from.
+var xlsxEpoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+const (
+	xlsxMainNs  = "http://schemas.openxmlformats.org/spreadsheetml/2006/main"
+	xlsxRelNs   = "http://schemas.openxmlformats.org/officeDocument/2006/relationships"
+	xlsxXmlDecl = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n"
+)
+
+// Resolves a column of a spreadsheet into a cell.
+type xlsxColumn func(t transactions.Transaction) xlsxCell
+
+// A spreadsheet cell, holding either a number or text.
+type xlsxCell struct {
+	value  string
+	number bool
+	style  int
+	// The currency of an amount, formatting the cell with its code.
+	currency string
+}
+
+// Writes transactions as an XLSX workbook.
+//
+// Dates and amounts are written as typed cells so they can be sorted and summed, with amounts
+// formatted in their currency. The transactions sheet is streamed, while the configured summary
+// sheets are aggregated and written on close.
+type XlsxWriter struct {
+	z          *zip.Writer
+	w          *bufio.Writer
+	cols       []xlsxColumn
+	rows       int
+	summaries  []*analysis.Summary
+	dateFmt    string
+	currencies []string
+}
+
+// Creates a writer of XLSX workbooks, using the columns and summaries of the output
+// configuration.
+func NewXlsxWriter(w io.Writer, c config.Config) (Writer, error) {
+	o := c.Output.WithDefaults()
+	cols, err := resolveXlsxColumns(o)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]*analysis.Summary, len(o.Summaries))
+	for i, s := range o.Summaries {
+		g, err := analysis.ParseGrouping(s)
+		if err != nil {
+			return nil, err
+		}
+		summaries[i] = analysis.NewSummary(g)
+	}
+
+	z := zip.NewWriter(w)
+	sheet, err := z.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, fmt.Errorf("xlsx file could not be written: %v", err)
+	}
+
+	xw := &XlsxWriter{
+		z:         z,
+		w:         bufio.NewWriter(sheet),
+		cols:      cols,
+		summaries: summaries,
+		dateFmt:   strings.ToLower(o.DateLayout),
+	}
+	xw.startSheet(true)
+	xw.row(xlsxHeader(o.Columns))
+	return xw, nil
+}
+
+// Writes a transaction as a row of the transactions sheet, adding it to the summaries unless it
+// is a transfer between own accounts.
+func (w *XlsxWriter) Write(t transactions.Transaction) error {
+	cells := make([]xlsxCell, len(w.cols))
+	for i, col := range w.cols {
+		cells[i] = col(t)
+	}
+	if !t.IsTransfer() {
+		for _, s := range w.summaries {
+			s.Add(t)
+		}
+	}
+	return w.row(cells)
+}
+
+// Finishes the transactions sheet, then writes the summary sheets and the workbook parts.
+func (w *XlsxWriter) Close() error {
+	last, rows := xlsxColumnName(len(w.cols)-1), w.rows
+	if err := w.endSheet(fmt.Sprintf("A1:%s%d", last, rows)); err != nil {
+		return err
+	}
+	filter := fmt.Sprintf("$A$1:$%s$%d", last, rows)
+
+	names := []string{"Transactions"}
+	for i, s := range w.summaries {
+		sheet, err := w.z.Create(fmt.Sprintf("xl/worksheets/sheet%d.xml", i+2))
+		if err != nil {
+			return fmt.Errorf("xlsx file could not be written: %v", err)
+		}
+		w.w.Reset(sheet)
+		w.rows = 0
+		w.writeSummary(s)
+		if err := w.endSheet(""); err != nil {
+			return err
+		}
+		names = append(names, xlsxSummaryNames[s.By])
+	}
+
+	parts := []struct{ name, content string }{
+		{"[Content_Types].xml", xlsxContentTypes(len(names))},
+		{"_rels/.rels", xlsxRootRels},
+		{"xl/workbook.xml", xlsxWorkbook(names, filter)},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels(len(names))},
+		{"xl/styles.xml", xlsxStyles(w.dateFmt, w.currencies)},
+	}
+	for _, p := range parts {
+		f, err := w.z.Create(p.name)
+		if err == nil {
+			_, err = io.WriteString(f, p.content)
+		}
+		if err != nil {
+			return fmt.Errorf("xlsx file could not be written: %v", err)
+		}
+	}
+
+	if err := w.z.Close(); err != nil {
+		return fmt.Errorf("xlsx file could not be written: %v", err)
+	}
+	return nil
+}
+
+// Writes the totals of a summary as a sheet, one row per group and currency.
+func (w *XlsxWriter) writeSummary(s *analysis.Summary) {
+	w.startSheet(false)
+	w.row(xlsxHeader([]string{string(s.By), "currency", "income", "spending", "net", "count"}))
+	for _, g := range s.Groups() {
+		w.row([]xlsxCell{
+			{value: g.Key},
+			{value: g.Currency},
+			xlsxAmount(g.Income, g.Currency),
+			xlsxAmount(g.Spending, g.Currency),
+			xlsxAmount(g.Net(), g.Currency),
+			{value: strconv.Itoa(g.Count), number: true},
+		})
+	}
+}
+
+// Writes the start of a sheet, optionally freezing its header row.
+func (w *XlsxWriter) startSheet(freeze bool) {
+	w.w.WriteString(xlsxXmlDecl)
+	fmt.Fprintf(w.w, `<worksheet xmlns="%s" xmlns:r="%s">`, xlsxMainNs, xlsxRelNs)
+	if freeze {
+		w.w.WriteString(`<sheetViews><sheetView workbookViewId="0">` +
+			`<pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/>` +
+			`<selection pane="bottomLeft"/></sheetView></sheetViews>`)
+	}
+	w.w.WriteString("<sheetData>")
+}
+
+// Writes the end of a sheet, adding an autofilter over the range if one is provided.
+func (w *XlsxWriter) endSheet(filter string) error {
+	w.w.WriteString("</sheetData>")
+	if filter != "" {
+		fmt.Fprintf(w.w, `<autoFilter ref="%s"/>`, filter)
+	}
+	w.w.WriteString("</worksheet>")
+	if err := w.w.Flush(); err != nil {
+		return fmt.Errorf("xlsx file could not be written: %v", err)
+	}
+	return nil
+}
+
+// Writes a row of cells to the current sheet.
+func (w *XlsxWriter) row(cells []xlsxCell) error {
+	w.rows++
+	fmt.Fprintf(w.w, `<row r="%d">`, w.rows)
+	for i, c := range cells {
+		ref := xlsxColumnName(i) + strconv.Itoa(w.rows)
+		if c.currency != "" {
+			c.style = w.currencyStyle(c.currency)
+		}
+		switch {
+		case c.number:
+			fmt.Fprintf(w.w, `<c r="%s" s="%d"><v>%s</v></c>`, ref, c.style, c.value)
+		case c.value != "":
+			fmt.Fprintf(w.w, `<c r="%s" s="%d" t="inlineStr"><is><t xml:space="preserve">`, ref, c.style)
+			xml.EscapeText(w.w, []byte(c.value))
+			w.w.WriteString("</t></is></c>")
+		}
+	}
+	if _, err := w.w.WriteString("</row>"); err != nil {
+		return fmt.Errorf("xlsx file could not be written: %v", err)
+	}
+	return nil
+}
+
+// The style of amounts in a currency, adding a format for the currency on its first use.
+func (w *XlsxWriter) currencyStyle(currency string) int {
+	i := slices.Index(w.currencies, currency)
+	if i < 0 {
+		i = len(w.currencies)
+		w.currencies = append(w.currencies, currency)
+	}
+	return xlsxStyleCurrency + i
+}
+
+// Resolves column names into spreadsheet columns, typing dates and amounts.
+func resolveXlsxColumns(o config.OutputConfig) ([]xlsxColumn, error) {
+	text, err := resolveColumns(o)
+	if err != nil {
+		return nil, err
+	}
+
+	cols := make([]xlsxColumn, len(o.Columns))
+	for i, name := range o.Columns {
+		switch name {
+		case "date":
+			cols[i] = func(t transactions.Transaction) xlsxCell { return xlsxDate(t.Date) }
+		case "valueDate":
+			cols[i] = func(t transactions.Transaction) xlsxCell { return xlsxDate(t.ValueDate) }
+		case "value":
+			cols[i] = func(t transactions.Transaction) xlsxCell { return xlsxAmount(t.Value, t.Currency) }
+		default:
+			col := text[i]
+			cols[i] = func(t transactions.Transaction) xlsxCell { return xlsxCell{value: col(t)} }
+		}
+	}
+	return cols, nil
+}
+
+// A row of bold header cells.
+func xlsxHeader(names []string) []xlsxCell {
+	cells := make([]xlsxCell, len(names))
+	for i, n := range names {
+		cells[i] = xlsxCell{value: n, style: xlsxStyleHeader}
+	}
+	return cells
+}
+
+// A date cell holding the serial number of the day, or an empty cell for a zero date.
+func xlsxDate(d time.Time) xlsxCell {
+	if d.IsZero() {
+		return xlsxCell{}
+	}
+	day := time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, time.UTC)
+	serial := int(day.Sub(xlsxEpoch).Hours() / 24)
+	return xlsxCell{value: strconv.Itoa(serial), number: true, style: xlsxStyleDate}
+}
+
+// An amount cell holding a value in minor units as a decimal number, formatted in its currency
+// if it has one.
+func xlsxAmount(v int, currency string) xlsxCell {
+	return xlsxCell{value: transactions.FormatValue(v, "."), number: true, style: xlsxStyleAmount, currency: currency}
+}
+
+// Converts a zero-based column index into its letter name, such as `A` or `AB`.
+func xlsxColumnName(i int) string {
+	name := ""
+	for i++; i > 0; i = (i - 1) / 26 {
+		name = string(rune('A'+(i-1)%26)) + name
+	}
+	return name
+}
+
+// The names of the sheets summarizing by a grouping.
+var xlsxSummaryNames = map[analysis.Grouping]string{
+	analysis.GroupDay:          "Days",
+	analysis.GroupWeek:         "Weeks",
+	analysis.GroupMonth:        "Months",
+	analysis.GroupQuarter:      "Quarters",
+	analysis.GroupYear:         "Years",
+	analysis.GroupCounterparty: "Counterparties",
+	analysis.GroupCategory:     "Categories",
+	analysis.GroupTag:          "Tags",
+}
+
+const xlsxRootRels = xlsxXmlDecl +
+	`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+func xlsxContentTypes(sheets int) string {
+	var b strings.Builder
+	b.WriteString(xlsxXmlDecl)
+	b.WriteString(`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">`)
+	b.WriteString(`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>`)
+	b.WriteString(`<Default Extension="xml" ContentType="application/xml"/>`)
+	b.WriteString(`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>`)
+	b.WriteString(`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>`)
+	for i := 1; i <= sheets; i++ {
+		fmt.Fprintf(&b, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, i)
+	}
+	b.WriteString(`</Types>`)
+	return b.String()
+}
+
+func xlsxWorkbook(names []string, filter string) string {
+	var b strings.Builder
+	b.WriteString(xlsxXmlDecl)
+	fmt.Fprintf(&b, `<workbook xmlns="%s" xmlns:r="%s"><sheets>`, xlsxMainNs, xlsxRelNs)
+	for i, n := range names {
+		fmt.Fprintf(&b, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, n, i+1, i+1)
+	}
+	b.WriteString(`</sheets><definedNames>`)
+	fmt.Fprintf(&b, `<definedName name="_xlnm._FilterDatabase" localSheetId="0" hidden="1">%s!%s</definedName>`, names[0], filter)
+	b.WriteString(`</definedNames></workbook>`)
+	return b.String()
+}
+
+func xlsxWorkbookRels(sheets int) string {
+	var b strings.Builder
+	b.WriteString(xlsxXmlDecl)
+	b.WriteString(`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+	for i := 1; i <= sheets; i++ {
+		fmt.Fprintf(&b, `<Relationship Id="rId%d" Type="%s/worksheet" Target="worksheets/sheet%d.xml"/>`, i, xlsxRelNs, i)
+	}
+	fmt.Fprintf(&b, `<Relationship Id="rId%d" Type="%s/styles" Target="styles.xml"/>`, sheets+1, xlsxRelNs)
+	b.WriteString(`</Relationships>`)
+	return b.String()
+}
+
+// The style sheet defining the cell formats in the order of the style constants, followed by the
+// amount formats of the currencies.
+func xlsxStyles(dateFmt string, currencies []string) string {
+	var b strings.Builder
+	b.WriteString(xlsxXmlDecl)
+	fmt.Fprintf(&b, `<styleSheet xmlns="%s">`, xlsxMainNs)
+	fmt.Fprintf(&b, `<numFmts count="%d"><numFmt numFmtId="164" formatCode="`, len(currencies)+1)
+	xml.EscapeText(&b, []byte(dateFmt))
+	b.WriteString(`"/>`)
+	for i, c := range currencies {
+		fmt.Fprintf(&b, `<numFmt numFmtId="%d" formatCode="`, 165+i)
+		xml.EscapeText(&b, []byte(`#,##0.00 "`+strings.ReplaceAll(c, `"`, "")+`"`))
+		b.WriteString(`"/>`)
+	}
+	b.WriteString(`</numFmts>`)
+	b.WriteString(`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>`)
+	b.WriteString(`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>`)
+	b.WriteString(`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>`)
+	b.WriteString(`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>`)
+	fmt.Fprintf(&b, `<cellXfs count="%d">`, len(currencies)+4)
+	b.WriteString(`<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>`)
+	b.WriteString(`<xf numFmtId="164" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>`)
+	b.WriteString(`<xf numFmtId="4" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>`)
+	b.WriteString(`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/>`)
+	for i := range currencies {
+		fmt.Fprintf(&b, `<xf numFmtId="%d" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>`, 165+i)
+	}
+	b.WriteString(`</cellXfs>`)
+	b.WriteString(`<cellStyles count="1"><cellStyle name="Normal" xfId="0" builtinId="0"/></cellStyles>`)
+	b.WriteString(`</styleSheet>`)
+	return b.String()
+}
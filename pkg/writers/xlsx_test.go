@@ -0,0 +1,163 @@
+package writers_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"statements/pkg/config"
+	"statements/pkg/transactions"
+	"statements/pkg/writers"
+	"strings"
+	"testing"
+)
+
+type xlsxSheet struct {
+	Pane struct {
+		State  string `xml:"state,attr"`
+		YSplit string `xml:"ySplit,attr"`
+	} `xml:"sheetViews>sheetView>pane"`
+	Rows []struct {
+		Cells []struct {
+			Ref    string `xml:"r,attr"`
+			Style  string `xml:"s,attr"`
+			Type   string `xml:"t,attr"`
+			Value  string `xml:"v"`
+			Inline string `xml:"is>t"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+	AutoFilter struct {
+		Ref string `xml:"ref,attr"`
+	} `xml:"autoFilter"`
+}
+
+// Writes the sample transactions as a workbook and parses its sheets.
+func writeXlsx(t *testing.T, c config.Config) map[string]xlsxSheet {
+	sheets := map[string]xlsxSheet{}
+	for name, data := range writeXlsxParts(t, c) {
+		var sheet xlsxSheet
+		if err := xml.Unmarshal(data, &sheet); err != nil {
+			t.Fatalf("%s could not be parsed: %v", name, err)
+		}
+		sheets[name] = sheet
+	}
+	return sheets
+}
+
+// Writes the sample transactions as a workbook and reads its parts.
+func writeXlsxParts(t *testing.T, c config.Config) map[string][]byte {
+	var buf bytes.Buffer
+	w, err := writers.New(writers.FormatXlsx, &buf, c)
+	if err != nil {
+		t.Fatalf("New() unexpected error: %v", err)
+	}
+	if err := writers.WriteAll(w, transactions.All(sampleTransactions())); err != nil {
+		t.Fatalf("WriteAll() unexpected error: %v", err)
+	}
+
+	z, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("XLSX output is not a zip archive: %v", err)
+	}
+
+	parts := map[string][]byte{}
+	for _, f := range z.File {
+		r, err := f.Open()
+		if err != nil {
+			t.Fatalf("%s could not be opened: %v", f.Name, err)
+		}
+		parts[f.Name], _ = io.ReadAll(r)
+		r.Close()
+	}
+	return parts
+}
+
+func TestXlsxWriter(t *testing.T) {
+	c := config.Config{Output: config.OutputConfig{Columns: []string{"date", "accountHolder", "value"}}}
+	parts := writeXlsx(t, c)
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/styles.xml"} {
+		if _, ok := parts[name]; !ok {
+			t.Errorf("workbook is missing part %s", name)
+		}
+	}
+
+	sheet := parts["xl/worksheets/sheet1.xml"]
+	if sheet.Pane.State != "frozen" || sheet.Pane.YSplit != "1" {
+		t.Errorf("pane = %+v, want the header row frozen", sheet.Pane)
+	}
+	if sheet.AutoFilter.Ref != "A1:C3" {
+		t.Errorf("autofilter = %q, want A1:C3", sheet.AutoFilter.Ref)
+	}
+	if len(sheet.Rows) != 3 {
+		t.Fatalf("sheet has %d rows, want 3", len(sheet.Rows))
+	}
+	if h := sheet.Rows[0].Cells[1]; h.Inline != "accountHolder" {
+		t.Errorf("header = %q, want accountHolder", h.Inline)
+	}
+
+	cells := sheet.Rows[2].Cells
+	if cells[0].Ref != "A3" || cells[0].Type != "" || cells[0].Value != "45673" {
+		t.Errorf("date cell = %+v, want the serial number 45673", cells[0])
+	}
+	if cells[1].Type != "inlineStr" || cells[1].Inline != "Shop" {
+		t.Errorf("text cell = %+v, want Shop", cells[1])
+	}
+	if cells[2].Value != "-12.05" || cells[2].Style != "4" {
+		t.Errorf("amount cell = %+v, want -12.05 in the EUR format", cells[2])
+	}
+
+	if _, ok := parts["xl/worksheets/sheet2.xml"]; ok {
+		t.Error("summary sheet written without being configured")
+	}
+}
+
+func TestXlsxWriterCurrencyFormats(t *testing.T) {
+	styles := string(writeXlsxParts(t, config.Config{})["xl/styles.xml"])
+	for _, want := range []string{
+		`<numFmt numFmtId="165" formatCode="#,##0.00 &#34;EUR&#34;"/>`,
+		`<cellXfs count="5">`,
+		`<xf numFmtId="165" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>`,
+	} {
+		if !strings.Contains(styles, want) {
+			t.Errorf("styles = %s, want %s", styles, want)
+		}
+	}
+}
+
+func TestXlsxWriterSummaries(t *testing.T) {
+	c := config.Config{Output: config.OutputConfig{Summaries: []string{"category", "month"}}}
+	parts := writeXlsx(t, c)
+
+	months, ok := parts["xl/worksheets/sheet3.xml"]
+	if !ok {
+		t.Fatal("month summary sheet not written")
+	}
+	if len(months.Rows) != 2 {
+		t.Fatalf("month summary has %d rows, want 2", len(months.Rows))
+	}
+	got := []string{}
+	for _, c := range months.Rows[1].Cells {
+		got = append(got, c.Value+c.Inline)
+	}
+	want := []string{"2025-01", "EUR", "1500.00", "-12.05", "1487.95", "2"}
+	if len(got) != len(want) {
+		t.Fatalf("month summary row = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("month summary row = %v, want %v", got, want)
+			break
+		}
+	}
+
+	if categories := parts["xl/worksheets/sheet2.xml"]; len(categories.Rows) != 2 || categories.Rows[1].Cells[0].Inline != "Uncategorized" {
+		t.Errorf("category summary = %+v, want a single uncategorized group", categories.Rows)
+	}
+}
+
+func TestXlsxWriterUnknownSummary(t *testing.T) {
+	c := config.Config{Output: config.OutputConfig{Summaries: []string{"weekday"}}}
+	if _, err := writers.New(writers.FormatXlsx, io.Discard, c); err == nil {
+		t.Error("New() expected an error for an unknown summary")
+	}
+}